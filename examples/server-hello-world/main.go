@@ -1,17 +1,15 @@
 package main
 
 import (
-	"io"
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/a2ap/a2ago/examples/server-hello-world/agent"
-	"github.com/a2ap/a2ago/internal/jsonrpc"
-	"github.com/a2ap/a2ago/internal/model"
-	"github.com/a2ap/a2ago/internal/service/server/impl"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server/impl"
 )
 
 func main() {
@@ -20,7 +18,7 @@ func main() {
 		Name:        "A2A Go Server",
 		Description: "A sample A2A agent implemented in Go",
 		Version:     "0.01",
-		URL:         "http://localhost:8089",
+		URL:         "http://localhost:8089/a2a/server",
 		Capabilities: &model.AgentCapabilities{
 			Streaming:              true,
 			PushNotifications:      false,
@@ -47,119 +45,79 @@ func main() {
 	// 6. 创建 Dispatcher，并注入 A2A Server
 	dispatcher := impl.NewDefaultDispatcher(a2aServer)
 
-	// 7. 创建 Gin 路由
-	router := gin.Default()
-
-	// 8. 配置 CORS
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"*"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// 9. 设置路由
-	// Serve frontend static files
-	router.Static("/assets", "./web/dist/assets")
-	router.StaticFile("/", "./web/dist/index.html")
-	router.StaticFile("/favicon.ico", "./web/dist/favicon.ico") // Optional: handle favicon
-
-	router.GET("/.well-known/agent.json", func(c *gin.Context) {
-		c.JSON(http.StatusOK, a2aServer.GetSelfAgentCard())
-	})
-
-	router.GET("/a2a/agent/authenticatedExtendedCard", func(c *gin.Context) {
+	// 7. 创建 A2A HTTP Handler（Agent Card、JSON-RPC、SSE 流式响应），并配置 CORS
+	handlerConfig := impl.DefaultHttpHandlerConfig()
+	handlerConfig.Cors = impl.DefaultCorsConfig()
+	handlerConfig.ExtendedCardAuthenticator = func(r *http.Request) error {
 		// This is a placeholder for actual authentication logic.
 		// In a real application, you would validate the API key against a database or a secure store.
-		apiKey := c.GetHeader("X-API-Key")
+		apiKey := r.Header.Get("X-API-Key")
 		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
-			return
+			return errors.New("API key required")
 		}
 
 		// Example of simple key check
 		if apiKey != "your-secure-api-key" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid API key"})
-			return
+			return impl.NewForbiddenError("Invalid API key")
 		}
+		return nil
+	}
+	a2aHandler := impl.NewHttpHandler(a2aServer, dispatcher, handlerConfig)
 
-		extendedCard, err := a2aServer.GetAuthenticatedExtendedCard(c.Request.Context())
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get extended agent card"})
-			return
-		}
-		c.JSON(http.StatusOK, extendedCard)
-	})
-
+	// 8. 设置路由
+	mux := http.NewServeMux()
+	mux.Handle(impl.DefaultAgentCardPath, a2aHandler)
+	mux.Handle(impl.DefaultJSONRPCPath, a2aHandler)
+	mux.Handle(impl.DefaultExtendedCardPath, a2aHandler)
 
-	router.POST("/a2a/server", func(c *gin.Context) {
-		// 检查是否为 SSE 流式请求
-		if c.GetHeader("Accept") == "text/event-stream" {
-			c.Header("Content-Type", "text/event-stream")
-			c.Header("Cache-Control", "no-cache")
-			c.Header("Connection", "keep-alive")
-
-			var request jsonrpc.JSONRPCRequest
-			if err := c.ShouldBindJSON(&request); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-
-			responses, err := dispatcher.DispatchStream(&request)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.Stream(func(w io.Writer) bool {
-				if response, ok := <-responses; ok {
-					c.SSEvent("task-update", response)
-					return true
-				}
-				return false
-			})
-		} else {
-			var request jsonrpc.JSONRPCRequest
-			if err := c.ShouldBindJSON(&request); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-
-			response := dispatcher.Dispatch(&request)
-			c.JSON(http.StatusOK, response)
+	// Serve frontend static files
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir("./web/dist/assets"))))
+	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "./web/dist/favicon.ico") // Optional: handle favicon
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
 		}
+		http.ServeFile(w, r, "./web/dist/index.html")
 	})
 
 	// 查询所有任务
-	router.GET("/a2a/tasks", func(c *gin.Context) {
-		tasks, err := a2aServer.ListTasks(c.Request.Context())
+	mux.Handle("/a2a/tasks", a2aHandler.WithCors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tasks, err := a2aServer.ListTasks(r.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, tasks)
-	})
+		writeJSON(w, http.StatusOK, tasks)
+	})))
 
 	// 查询单个任务详情
-	router.GET("/a2a/task/:id", func(c *gin.Context) {
-		taskID := c.Param("id")
-		task, err := a2aServer.GetTask(c.Request.Context(), taskID)
+	mux.Handle("/a2a/task/", a2aHandler.WithCors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		taskID := strings.TrimPrefix(r.URL.Path, "/a2a/task/")
+		task, err := a2aServer.GetTask(r.Context(), taskID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
 		if task == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "Task not found"})
 			return
 		}
-		c.JSON(http.StatusOK, task)
-	})
+		writeJSON(w, http.StatusOK, task)
+	})))
 
-	// 10. 启动服务
+	// 9. 启动服务
 	log.Println("Starting A2A server on http://localhost:8089")
-	if err := router.Run(":8089"); err != nil {
+	if err := http.ListenAndServe(":8089", mux); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// writeJSON writes a JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
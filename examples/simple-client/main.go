@@ -6,9 +6,8 @@ import (
 	"log"
 	"time"
 
-	"github.com/a2ap/a2ago/internal/model"
-	clientimpl "github.com/a2ap/a2ago/internal/service/client/impl"
-	"github.com/a2ap/a2ago/internal/util"
+	"github.com/a2ap/a2ago/pkg/model"
+	clientimpl "github.com/a2ap/a2ago/pkg/service/client/impl"
 )
 
 func main() {
@@ -24,7 +23,7 @@ func main() {
 
 	// 创建消息
 	textPart := model.NewTextPart("Hello, this is a test message")
-	message := model.NewMessage(util.GenerateUUID(), "user", []model.Part{textPart})
+	message := model.NewUserMessage("", "", []model.Part{textPart})
 
 	// 创建消息发送参数
 	metadata := make(map[string]interface{})
@@ -90,7 +90,9 @@ function App() {
         method: "message/send",
         params: {
           message: {
-            parts: [{ type: "text", text: inputText }],
+            kind: "message",
+            messageId: crypto.randomUUID(),
+            parts: [{ kind: "text", type: "text", text: inputText }],
             role: "user"
           },
           config: { blocking: false, acceptedOutputModes: ["text", "json"] }
@@ -1,6 +1,7 @@
 package exception
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -46,8 +47,82 @@ const (
 
 	// AuthorizationError indicates that authorization failed
 	AuthorizationError = 1005
+
+	// InvalidTaskStateTransition indicates that a status update is not allowed by the task lifecycle
+	InvalidTaskStateTransition = 1006
+
+	// ExecutionTimeout indicates that the task failed because its execution deadline passed
+	ExecutionTimeout = 1007
+
+	// ServerBusy indicates that the server runs as many tasks as it admits and has no room to queue another
+	ServerBusy = 1008
+
+	// TaskNotCancelable indicates that the task is in a final state and can no longer be canceled
+	TaskNotCancelable = -32002
+
+	// UnsupportedOperation indicates that the operation is not supported for the task in its current state
+	UnsupportedOperation = -32004
 )
 
+// NewTaskNotCancelableError creates the error returned when canceling a task in a final state
+func NewTaskNotCancelableError(taskID string, state string) *A2AError {
+	return NewA2AErrorWithAll(
+		fmt.Sprintf("Task cannot be canceled in state %s", state),
+		TaskNotCancelable,
+		map[string]interface{}{"taskId": taskID, "state": state},
+		taskID,
+	)
+}
+
+// NewAgentExecutionError creates the error reported when the agent executor of a task failed.
+// The message is sent to clients, the cause is only kept for logging.
+func NewAgentExecutionError(taskID string, message string, cause error) *A2AError {
+	return NewA2AErrorWithAllAndCause(
+		message,
+		cause,
+		AgentExecutionError,
+		map[string]interface{}{"taskId": taskID, "state": "failed"},
+		taskID,
+	)
+}
+
+// NewExecutionTimeoutError creates the error reported when a task failed because its execution deadline passed
+func NewExecutionTimeoutError(taskID string) *A2AError {
+	return NewA2AErrorWithAll(
+		"Task execution timed out",
+		ExecutionTimeout,
+		map[string]interface{}{"taskId": taskID, "state": "failed", "reason": "timeout"},
+		taskID,
+	)
+}
+
+// NewServerBusyError creates the error returned when a task is rejected because the server is at capacity
+func NewServerBusyError(running, pending int) *A2AError {
+	return NewA2AErrorWithAll(
+		"Server is busy, retry later",
+		ServerBusy,
+		map[string]interface{}{"running": running, "pending": pending},
+		"",
+	)
+}
+
+// NewInvalidParamsError creates the error returned when the parameters of a request are malformed
+func NewInvalidParamsError(cause error) *A2AError {
+	return NewA2AErrorWithAllAndCause(
+		"Invalid params",
+		cause,
+		InvalidParams,
+		cause.Error(),
+		"",
+	)
+}
+
+// HasCode reports whether err is or wraps an A2AError with the given code
+func HasCode(err error, code int) bool {
+	var a2aErr *A2AError
+	return errors.As(err, &a2aErr) && a2aErr.Code == code
+}
+
 // NewA2AError creates a new A2A error with a message
 func NewA2AError(message string) *A2AError {
 	return &A2AError{
@@ -0,0 +1,74 @@
+package exception
+
+import (
+	"fmt"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// InvalidTransitionError is returned when a status update is not allowed by the task lifecycle.
+// Create it with NewInvalidTransitionError.
+type InvalidTransitionError struct {
+	// TaskID is the ID of the task
+	TaskID string
+	// From is the current state of the task
+	From model.TaskState
+	// To is the rejected target state
+	To model.TaskState
+
+	a2aError *A2AError
+}
+
+// NewInvalidTransitionError creates a new InvalidTransitionError
+func NewInvalidTransitionError(taskID string, from, to model.TaskState) *InvalidTransitionError {
+	e := &InvalidTransitionError{
+		TaskID: taskID,
+		From:   from,
+		To:     to,
+	}
+	e.a2aError = NewA2AErrorWithAll(e.Error(), InvalidTaskStateTransition,
+		map[string]interface{}{"taskId": taskID, "from": from, "to": to}, taskID)
+	return e
+}
+
+// Error returns the error message
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid state transition for task %s: %s -> %s", e.TaskID, e.From, e.To)
+}
+
+// Unwrap returns the A2A error reported to clients
+func (e *InvalidTransitionError) Unwrap() error {
+	return e.a2aError
+}
+
+// TaskTerminalError is returned when a task in a final state would be changed or receive a new message.
+// Create it with NewTaskTerminalError.
+type TaskTerminalError struct {
+	// TaskID is the ID of the task
+	TaskID string
+	// State is the final state of the task
+	State model.TaskState
+
+	a2aError *A2AError
+}
+
+// NewTaskTerminalError creates a new TaskTerminalError
+func NewTaskTerminalError(taskID string, state model.TaskState) *TaskTerminalError {
+	e := &TaskTerminalError{
+		TaskID: taskID,
+		State:  state,
+	}
+	e.a2aError = NewA2AErrorWithAll(e.Error(), UnsupportedOperation,
+		map[string]interface{}{"taskId": taskID, "state": state}, taskID)
+	return e
+}
+
+// Error returns the error message
+func (e *TaskTerminalError) Error() string {
+	return fmt.Sprintf("task %s is %s and can no longer be changed", e.TaskID, e.State)
+}
+
+// Unwrap returns the A2A error reported to clients
+func (e *TaskTerminalError) Unwrap() error {
+	return e.a2aError
+}
@@ -1,5 +1,7 @@
 package jsonrpc
 
+import "fmt"
+
 // JSONRPCRequest represents a JSON-RPC request
 type JSONRPCRequest struct {
 	ID     string      `json:"id"`
@@ -12,6 +14,8 @@ type JSONRPCResponse struct {
 	ID     string        `json:"id"`
 	Result interface{}   `json:"result,omitempty"`
 	Error  *JSONRPCError `json:"error,omitempty"`
+	// EventID identifies a streamed response on the transport, e.g. the SSE id field; it is not serialized
+	EventID string `json:"-"`
 }
 
 // JSONRPCError represents a JSON-RPC error
@@ -21,6 +25,14 @@ type JSONRPCError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Error implements the error interface
+func (e *JSONRPCError) Error() string {
+	if e.Data != nil {
+		return fmt.Sprintf("%s (code %d): %v", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
 // JSON-RPC error codes
 const (
 	ParseError     = -32700
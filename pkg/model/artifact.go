@@ -39,6 +39,9 @@ type Artifact struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// ArtifactLastChunkMetadataKey is the metadata key that marks an artifact whose last chunk was applied
+const ArtifactLastChunkMetadataKey = "lastChunk"
+
 // NewArtifact creates a new Artifact with the given ID and name
 func NewArtifact(artifactID, name string) *Artifact {
 	return &Artifact{
@@ -83,6 +86,52 @@ func (a *Artifact) SetMetadata(key string, value interface{}) *Artifact {
 	return a
 }
 
+// IsComplete reports whether the last chunk of the artifact was applied
+func (a *Artifact) IsComplete() bool {
+	complete, _ := a.Metadata[ArtifactLastChunkMetadataKey].(bool)
+	return complete
+}
+
+// SetComplete records in the metadata whether the last chunk of the artifact was applied
+func (a *Artifact) SetComplete(complete bool) *Artifact {
+	if complete {
+		return a.SetMetadata(ArtifactLastChunkMetadataKey, true)
+	}
+	delete(a.Metadata, ArtifactLastChunkMetadataKey)
+	return a
+}
+
+// Clone returns a copy of the artifact that shares no parts list or metadata map with it
+func (a *Artifact) Clone() *Artifact {
+	clone := *a
+	clone.Parts = append(make([]Part, 0, len(a.Parts)), a.Parts...)
+	if a.Metadata != nil {
+		clone.Metadata = make(map[string]interface{}, len(a.Metadata))
+		for key, value := range a.Metadata {
+			clone.Metadata[key] = value
+		}
+	}
+	return &clone
+}
+
+// AppendChunk returns a copy of the artifact extended with a streamed chunk of it:
+// the parts of chunk follow the existing parts, a non-empty name or description replaces
+// the current one and the metadata of chunk is merged in. Neither artifact is modified.
+func (a *Artifact) AppendChunk(chunk *Artifact) *Artifact {
+	merged := a.Clone()
+	merged.Parts = append(merged.Parts, chunk.Parts...)
+	if chunk.Name != "" {
+		merged.Name = chunk.Name
+	}
+	if chunk.Description != "" {
+		merged.Description = chunk.Description
+	}
+	for key, value := range chunk.Metadata {
+		merged.SetMetadata(key, value)
+	}
+	return merged
+}
+
 // MarshalJSON implements the json.Marshaler interface
 func (a *Artifact) MarshalJSON() ([]byte, error) {
 	type Alias Artifact
@@ -93,16 +142,24 @@ func (a *Artifact) MarshalJSON() ([]byte, error) {
 	})
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Parts are decoded into the concrete type registered for their kind.
 func (a *Artifact) UnmarshalJSON(data []byte) error {
 	type Alias Artifact
 	aux := &struct {
 		*Alias
+		Parts []json.RawMessage `json:"parts"`
 	}{
 		Alias: (*Alias)(a),
 	}
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+
+	parts, err := UnmarshalParts(aux.Parts)
+	if err != nil {
+		return err
+	}
+	a.Parts = parts
 	return nil
 }
@@ -0,0 +1,76 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// partTexts returns the texts of the text parts of an artifact
+func partTexts(artifact *model.Artifact) []string {
+	texts := make([]string, 0, len(artifact.Parts))
+	for _, part := range artifact.Parts {
+		texts = append(texts, part.(*model.TextPart).Text)
+	}
+	return texts
+}
+
+func TestArtifactAppendChunk(t *testing.T) {
+	first := model.NewArtifact("report", "Draft").
+		WithDescription("first").
+		WithParts([]model.Part{model.NewTextPart("one")}).
+		WithMetadata(map[string]interface{}{"kept": true, "replaced": 1})
+	chunk := model.NewArtifact("report", "").
+		WithParts([]model.Part{model.NewTextPart("two"), model.NewTextPart("three")}).
+		WithMetadata(map[string]interface{}{"replaced": 2})
+
+	merged := first.AppendChunk(chunk)
+
+	if got := partTexts(merged); len(got) != 3 || got[0] != "one" || got[1] != "two" || got[2] != "three" {
+		t.Errorf("AppendChunk() parts = %v, want [one two three]", got)
+	}
+	if merged.Name != "Draft" || merged.Description != "first" {
+		t.Errorf("AppendChunk() = %s/%s, want the name and description kept when the chunk has none", merged.Name, merged.Description)
+	}
+	if merged.Metadata["kept"] != true || merged.Metadata["replaced"] != 2 {
+		t.Errorf("AppendChunk() metadata = %v, want the chunk metadata merged in", merged.Metadata)
+	}
+	if len(first.Parts) != 1 || first.Metadata["replaced"] != 1 || len(chunk.Parts) != 2 {
+		t.Error("AppendChunk() modified one of the artifacts")
+	}
+
+	renamed := merged.AppendChunk(model.NewArtifact("report", "Report").WithDescription("final"))
+	if renamed.Name != "Report" || renamed.Description != "final" || len(renamed.Parts) != 3 {
+		t.Errorf("AppendChunk() = %+v, want the name and description of the chunk", renamed)
+	}
+}
+
+func TestArtifactCloneSharesNoParts(t *testing.T) {
+	artifact := model.NewArtifact("a", "A").WithParts([]model.Part{model.NewTextPart("one")})
+	clone := artifact.Clone()
+	clone.AddPart(model.NewTextPart("two"))
+	clone.Parts[0] = nil
+	clone.SetMetadata("key", "value")
+
+	if len(artifact.Parts) != 1 || artifact.Parts[0] == nil || artifact.Metadata != nil {
+		t.Errorf("artifact = %+v, want it unchanged by its clone", artifact)
+	}
+}
+
+func TestArtifactCompletionIsKeptInMetadata(t *testing.T) {
+	artifact := model.NewArtifact("report", "Report").SetMetadata("owner", "agent")
+	if artifact.IsComplete() {
+		t.Fatal("new artifact is complete, want it open")
+	}
+	artifact.SetComplete(true)
+	if !artifact.IsComplete() || artifact.Metadata[model.ArtifactLastChunkMetadataKey] != true {
+		t.Fatalf("metadata = %v, want the artifact marked complete", artifact.Metadata)
+	}
+	if !artifact.Clone().IsComplete() {
+		t.Error("clone of a complete artifact is open, want it complete")
+	}
+	artifact.SetComplete(false)
+	if artifact.IsComplete() || len(artifact.Metadata) != 1 {
+		t.Errorf("metadata = %v, want only the owner left", artifact.Metadata)
+	}
+}
@@ -39,29 +39,41 @@ func (p *DataPart) SetMetadata(key string, value interface{}) *DataPart {
 	return p
 }
 
+// GetContent returns the content of the part
+func (p *DataPart) GetContent() interface{} {
+	return p.Data
+}
+
 // MarshalJSON implements the json.Marshaler interface
 func (p *DataPart) MarshalJSON() ([]byte, error) {
-	type Alias DataPart
-	return json.Marshal(&struct {
-		*Alias
-		Type PartType `json:"type"`
+	aux := struct {
+		Kind     string                 `json:"kind"`
+		Metadata map[string]interface{} `json:"metadata,omitempty"`
+		Type     PartType               `json:"type"`
+		Data     interface{}            `json:"data"`
 	}{
-		Alias: (*Alias)(p),
-		Type:  p.GetType(),
-	})
+		Kind:     string(PartTypeData),
+		Metadata: p.Metadata,
+		Type:     PartTypeData,
+		Data:     p.Data,
+	}
+	return json.Marshal(aux)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface
 func (p *DataPart) UnmarshalJSON(data []byte) error {
-	type Alias DataPart
-	aux := &struct {
-		*Alias
-		Type PartType `json:"type"`
-	}{
-		Alias: (*Alias)(p),
+	var aux struct {
+		Kind     string                 `json:"kind"`
+		Metadata map[string]interface{} `json:"metadata,omitempty"`
+		Type     PartType               `json:"type"`
+		Data     interface{}            `json:"data"`
 	}
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	p.BasePart.Kind = string(PartTypeData)
+	p.BasePart.Metadata = aux.Metadata
+	p.BasePart.Type = PartTypeData
+	p.Data = aux.Data
 	return nil
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/a2ap/a2ago/internal/util"
@@ -81,3 +82,23 @@ func (e *TaskStatusUpdateEvent) IsSendStreamingMessageResponse() {}
 
 // IsSendStreamingMessageResponse implements the SendStreamingMessageResponse interface
 func (e *TaskArtifactUpdateEvent) IsSendStreamingMessageResponse() {}
+
+// MarshalJSON implements the json.Marshaler interface
+func (e *TaskStatusUpdateEvent) MarshalJSON() ([]byte, error) {
+	type Alias TaskStatusUpdateEvent
+	aux := *(*Alias)(e)
+	if aux.Kind == "" {
+		aux.Kind = KindStatusUpdate
+	}
+	return json.Marshal(aux)
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (e *TaskArtifactUpdateEvent) MarshalJSON() ([]byte, error) {
+	type Alias TaskArtifactUpdateEvent
+	aux := *(*Alias)(e)
+	if aux.Kind == "" {
+		aux.Kind = KindArtifactUpdate
+	}
+	return json.Marshal(aux)
+}
@@ -4,6 +4,44 @@ import (
 	"encoding/json"
 )
 
+// File is the content carried by a FilePart.
+// The protocol variants are *FileWithBytes and *FileWithUri.
+type File interface {
+	// GetName returns the name of the file
+	GetName() string
+	// GetMimeType returns the MIME type of the file
+	GetMimeType() string
+}
+
+// UnmarshalFile decodes file content into *FileWithBytes when it carries "bytes"
+// and into *FileWithUri otherwise
+func UnmarshalFile(data []byte) (File, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var probe struct {
+		Bytes *string `json:"bytes"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Bytes != nil {
+		file := &FileWithBytes{}
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, err
+		}
+		return file, nil
+	}
+
+	file := &FileWithUri{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
 // FileContent represents the content of a file
 type FileContent struct {
 	// ID is the unique identifier of the file
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+// FilePart represents a file part of a message
+type FilePart struct {
+	BasePart
+	// File is the file content, either *FileWithBytes or *FileWithUri
+	File File `json:"file"`
+}
+
+// NewFilePart creates a new FilePart with the given file content
+func NewFilePart(file File) *FilePart {
+	return &FilePart{
+		BasePart: BasePart{
+			Kind: "file",
+			Type: PartTypeFile,
+		},
+		File: file,
+	}
+}
+
+// GetType returns the type of the part
+func (p *FilePart) GetType() PartType {
+	return PartTypeFile
+}
+
+// WithMetadata sets the metadata for the file part
+func (p *FilePart) WithMetadata(metadata map[string]interface{}) *FilePart {
+	p.BasePart.Metadata = metadata
+	return p
+}
+
+// SetMetadata sets a metadata key-value pair
+func (p *FilePart) SetMetadata(key string, value interface{}) *FilePart {
+	p.BasePart.SetMetadata(key, value)
+	return p
+}
+
+// GetContent returns the content of the part
+func (p *FilePart) GetContent() interface{} {
+	return p.File
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (p *FilePart) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Kind     string                 `json:"kind"`
+		Metadata map[string]interface{} `json:"metadata,omitempty"`
+		Type     PartType               `json:"type"`
+		File     File                   `json:"file"`
+	}{
+		Kind:     string(PartTypeFile),
+		Metadata: p.Metadata,
+		Type:     PartTypeFile,
+		File:     p.File,
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The file is decoded as *FileWithBytes when it carries "bytes" and as *FileWithUri otherwise.
+func (p *FilePart) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Kind     string                 `json:"kind"`
+		Metadata map[string]interface{} `json:"metadata,omitempty"`
+		Type     PartType               `json:"type"`
+		File     json.RawMessage        `json:"file"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	file, err := UnmarshalFile(aux.File)
+	if err != nil {
+		return err
+	}
+	p.BasePart.Kind = string(PartTypeFile)
+	p.BasePart.Metadata = aux.Metadata
+	p.BasePart.Type = PartTypeFile
+	p.File = file
+	return nil
+}
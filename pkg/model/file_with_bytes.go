@@ -9,7 +9,7 @@ import (
 type FileWithBytes struct {
 	BaseFileContent
 	// Content is the base64-encoded content
-	Content string `json:"content"`
+	Content string `json:"bytes"`
 }
 
 // NewFileWithBytes creates a new FileWithBytes with the given content
@@ -42,24 +42,30 @@ func (f *FileWithBytes) WithMimeType(mimeType string) *FileWithBytes {
 
 // MarshalJSON implements the json.Marshaler interface
 func (f *FileWithBytes) MarshalJSON() ([]byte, error) {
-	type Alias FileWithBytes
-	return json.Marshal(&struct {
-		*Alias
+	aux := struct {
+		Name     string `json:"name,omitempty"`
+		MimeType string `json:"mimeType,omitempty"`
+		Content  string `json:"bytes"`
 	}{
-		Alias: (*Alias)(f),
-	})
+		Name:     f.Name,
+		MimeType: f.MimeType,
+		Content:  f.Content,
+	}
+	return json.Marshal(aux)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface
 func (f *FileWithBytes) UnmarshalJSON(data []byte) error {
-	type Alias FileWithBytes
-	aux := &struct {
-		*Alias
-	}{
-		Alias: (*Alias)(f),
+	var aux struct {
+		Name     string `json:"name,omitempty"`
+		MimeType string `json:"mimeType,omitempty"`
+		Content  string `json:"bytes"`
 	}
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	f.Name = aux.Name
+	f.MimeType = aux.MimeType
+	f.Content = aux.Content
 	return nil
 }
@@ -43,24 +43,30 @@ func (f *FileWithUri) WithMimeType(mimeType string) *FileWithUri {
 
 // MarshalJSON implements the json.Marshaler interface
 func (f *FileWithUri) MarshalJSON() ([]byte, error) {
-	type Alias FileWithUri
-	return json.Marshal(&struct {
-		*Alias
+	aux := struct {
+		Name     string `json:"name,omitempty"`
+		MimeType string `json:"mimeType,omitempty"`
+		URI      string `json:"uri"`
 	}{
-		Alias: (*Alias)(f),
-	})
+		Name:     f.Name,
+		MimeType: f.MimeType,
+		URI:      f.URI,
+	}
+	return json.Marshal(aux)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface
 func (f *FileWithUri) UnmarshalJSON(data []byte) error {
-	type Alias FileWithUri
-	aux := &struct {
-		*Alias
-	}{
-		Alias: (*Alias)(f),
+	var aux struct {
+		Name     string `json:"name,omitempty"`
+		MimeType string `json:"mimeType,omitempty"`
+		URI      string `json:"uri"`
 	}
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	f.Name = aux.Name
+	f.MimeType = aux.MimeType
+	f.URI = aux.URI
 	return nil
 }
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Kind discriminators used on the wire by the A2A protocol
+const (
+	// KindTask identifies a Task
+	KindTask = "task"
+	// KindMessage identifies a Message
+	KindMessage = "message"
+	// KindStatusUpdate identifies a TaskStatusUpdateEvent
+	KindStatusUpdate = "status-update"
+	// KindArtifactUpdate identifies a TaskArtifactUpdateEvent
+	KindArtifactUpdate = "artifact-update"
+)
+
+// PartFactory creates an empty Part to decode a registered part kind into
+type PartFactory func() Part
+
+// ResponseFactory creates an empty streaming response to decode a registered kind into
+type ResponseFactory func() SendStreamingMessageResponse
+
+var (
+	registryMu sync.RWMutex
+
+	partKinds = map[string]PartFactory{
+		string(PartTypeText): func() Part { return &TextPart{} },
+		string(PartTypeFile): func() Part { return &FilePart{} },
+		string(PartTypeData): func() Part { return &DataPart{} },
+	}
+
+	responseKinds = map[string]ResponseFactory{
+		KindTask:           func() SendStreamingMessageResponse { return &Task{} },
+		KindMessage:        func() SendStreamingMessageResponse { return &Message{} },
+		KindStatusUpdate:   func() SendStreamingMessageResponse { return &TaskStatusUpdateEvent{} },
+		KindArtifactUpdate: func() SendStreamingMessageResponse { return &TaskArtifactUpdateEvent{} },
+	}
+)
+
+// RegisterPartKind registers a factory used to decode parts of the given kind.
+// Registering an existing kind replaces its factory.
+func RegisterPartKind(kind string, factory PartFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	partKinds[kind] = factory
+}
+
+// RegisterResponseKind registers a factory used to decode streaming responses of the given kind.
+// Registering an existing kind replaces its factory.
+func RegisterResponseKind(kind string, factory ResponseFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	responseKinds[kind] = factory
+}
+
+// kindOf reads the "kind" discriminator of a JSON object, falling back to the legacy "type" field
+func kindOf(data []byte) (string, error) {
+	var discriminator struct {
+		Kind string `json:"kind"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &discriminator); err != nil {
+		return "", err
+	}
+	if discriminator.Kind != "" {
+		return discriminator.Kind, nil
+	}
+	return discriminator.Type, nil
+}
+
+// UnmarshalPart decodes a single part into the concrete type registered for its kind
+func UnmarshalPart(data []byte) (Part, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	kind, err := kindOf(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read part kind: %w", err)
+	}
+
+	registryMu.RLock()
+	factory, ok := partKinds[kind]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown part kind: %q", kind)
+	}
+
+	part := factory()
+	if err := json.Unmarshal(data, part); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s part: %w", kind, err)
+	}
+	return part, nil
+}
+
+// UnmarshalParts decodes a list of raw parts
+func UnmarshalParts(data []json.RawMessage) ([]Part, error) {
+	if data == nil {
+		return nil, nil
+	}
+	parts := make([]Part, 0, len(data))
+	for i, partData := range data {
+		part, err := UnmarshalPart(partData)
+		if err != nil {
+			return nil, fmt.Errorf("part[%d]: %w", i, err)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// UnmarshalStreamingResponse decodes a streaming result into the concrete type registered for its kind:
+// *Task, *Message, *TaskStatusUpdateEvent or *TaskArtifactUpdateEvent
+func UnmarshalStreamingResponse(data []byte) (SendStreamingMessageResponse, error) {
+	kind, err := kindOf(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response kind: %w", err)
+	}
+
+	registryMu.RLock()
+	factory, ok := responseKinds[kind]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown response kind: %q", kind)
+	}
+
+	response := factory()
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", kind, err)
+	}
+	return response, nil
+}
+
+// UnmarshalSendMessageResponse decodes a message/send result into a *Task or a *Message
+func UnmarshalSendMessageResponse(data []byte) (SendMessageResponse, error) {
+	response, err := UnmarshalStreamingResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := response.(SendMessageResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message/send result type: %T", response)
+	}
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package model_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+func TestPartsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		part model.Part
+	}{
+		{"text", model.NewTextPart("hello").WithMetadata(map[string]interface{}{"lang": "en"})},
+		{"file with bytes", model.NewFilePart(model.NewFileWithBytes([]byte("content"), "a.txt", "text/plain"))},
+		{"file with uri", model.NewFilePart(model.NewFileWithUriWithMetadata("b.png", "image/png", "https://example.com/b.png"))},
+		{"file with uri only", model.NewFilePart(model.NewFileWithUri("https://example.com/c"))},
+		{"data", model.NewDataPart(map[string]interface{}{"city": "Paris", "days": float64(3), "tags": []interface{}{"a", "b"}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.part)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			got, err := model.UnmarshalPart(data)
+			if err != nil {
+				t.Fatalf("UnmarshalPart(%s) error = %v", data, err)
+			}
+			if !reflect.DeepEqual(got, tt.part) {
+				t.Errorf("UnmarshalPart(%s) = %#v, want %#v", data, got, tt.part)
+			}
+		})
+	}
+}
+
+func TestUnmarshalFileVariants(t *testing.T) {
+	part, err := model.UnmarshalPart([]byte(`{"kind":"file","file":{"name":"a.txt","mimeType":"text/plain","bytes":"aGVsbG8="}}`))
+	if err != nil {
+		t.Fatalf("UnmarshalPart() error = %v", err)
+	}
+	withBytes, ok := part.(*model.FilePart).File.(*model.FileWithBytes)
+	if !ok {
+		t.Fatalf("File = %T, want *model.FileWithBytes", part.(*model.FilePart).File)
+	}
+	if content, err := withBytes.GetBytes(); err != nil || string(content) != "hello" {
+		t.Errorf("GetBytes() = %q, %v, want hello", content, err)
+	}
+
+	part, err = model.UnmarshalPart([]byte(`{"kind":"file","file":{"uri":"https://example.com/a"}}`))
+	if err != nil {
+		t.Fatalf("UnmarshalPart() error = %v", err)
+	}
+	if withURI, ok := part.(*model.FilePart).File.(*model.FileWithUri); !ok || withURI.URI != "https://example.com/a" {
+		t.Errorf("File = %#v, want *model.FileWithUri with the URI", part.(*model.FilePart).File)
+	}
+}
+
+func TestUnmarshalParts(t *testing.T) {
+	raw := []json.RawMessage{
+		json.RawMessage(`{"kind":"text","text":"hello"}`),
+		json.RawMessage(`{"type":"data","data":{"n":1}}`),
+	}
+	parts, err := model.UnmarshalParts(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalParts() error = %v", err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("UnmarshalParts() = %d parts, want 2", len(parts))
+	}
+	if text, ok := parts[0].(*model.TextPart); !ok || text.Text != "hello" {
+		t.Errorf("parts[0] = %#v, want the text part", parts[0])
+	}
+	if _, ok := parts[1].(*model.DataPart); !ok {
+		t.Errorf("parts[1] = %T, want *model.DataPart decoded from the legacy type field", parts[1])
+	}
+
+	if parts, err := model.UnmarshalParts(nil); err != nil || parts != nil {
+		t.Errorf("UnmarshalParts(nil) = %v, %v, want nil, nil", parts, err)
+	}
+}
+
+func TestUnmarshalPartRejectsUnknownKind(t *testing.T) {
+	if _, err := model.UnmarshalPart([]byte(`{"kind":"video","url":"x"}`)); err == nil || !strings.Contains(err.Error(), `unknown part kind: "video"`) {
+		t.Errorf("UnmarshalPart() error = %v, want the unknown part kind", err)
+	}
+
+	raw := []json.RawMessage{json.RawMessage(`{"kind":"text","text":"ok"}`), json.RawMessage(`{"kind":"video"}`)}
+	if _, err := model.UnmarshalParts(raw); err == nil || !strings.HasPrefix(err.Error(), "part[1]: ") {
+		t.Errorf("UnmarshalParts() error = %v, want the index of the unknown part", err)
+	}
+
+	if _, err := model.UnmarshalStreamingResponse([]byte(`{"kind":"unknown"}`)); err == nil || !strings.Contains(err.Error(), `unknown response kind: "unknown"`) {
+		t.Errorf("UnmarshalStreamingResponse() error = %v, want the unknown response kind", err)
+	}
+}
+
+func TestMessagePartsRoundTrip(t *testing.T) {
+	message := model.NewUserMessage("task-1", "context-1", []model.Part{
+		model.NewTextPart("hello"),
+		model.NewFilePart(model.NewFileWithBytes([]byte("content"), "a.txt", "text/plain")),
+		model.NewDataPart(map[string]interface{}{"n": float64(1)}),
+	})
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got model.Message
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", data, err)
+	}
+	if !reflect.DeepEqual(got.Parts, message.Parts) {
+		t.Errorf("Parts = %#v, want %#v", got.Parts, message.Parts)
+	}
+}
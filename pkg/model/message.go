@@ -0,0 +1,450 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2ap/a2ago/internal/util"
+)
+
+// MessageResponse represents the response from sending a message
+type MessageResponse struct {
+	TaskID string `json:"taskId"`
+	Status string `json:"status"`
+}
+
+// Role identifies the sender of a message
+type Role string
+
+const (
+	// RoleUser marks a message sent by the client
+	RoleUser Role = "user"
+	// RoleAgent marks a message sent by the agent
+	RoleAgent Role = "agent"
+)
+
+// String returns the string representation of the role
+func (r Role) String() string {
+	return string(r)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling
+func (r *Role) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch Role(str) {
+	case RoleUser, RoleAgent:
+		*r = Role(str)
+		return nil
+	default:
+		return fmt.Errorf("unknown Role value: %s", str)
+	}
+}
+
+// Message represents a message in the A2A system
+type Message struct {
+	// MessageID is the unique identifier of the message, generated by its sender
+	MessageID string `json:"messageId"`
+	// TaskID is the ID of the task this message belongs to
+	TaskID string `json:"taskId,omitempty"`
+	// ContextID is the ID of the context this message belongs to
+	ContextID string `json:"contextId,omitempty"`
+	// Parts is the list of message parts
+	Parts []Part `json:"parts"`
+	// Metadata is the metadata associated with the message
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Kind is the kind of message, always "message"
+	Kind string `json:"kind,omitempty"`
+	// Role is the sender of the message, user or agent
+	Role Role `json:"role,omitempty"`
+	// ReferenceTaskIDs are the IDs of other tasks the message refers to
+	ReferenceTaskIDs []string `json:"referenceTaskIds,omitempty"`
+	// Extensions are the URIs of the protocol extensions that contributed to the message
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// MessagePart represents a part of a message
+type MessagePart struct {
+	Type    string      `json:"type"`
+	Content interface{} `json:"content"`
+}
+
+// MessageSendParams represents the parameters for sending a message
+type MessageSendParams struct {
+	// Message is the message to send
+	Message *Message `json:"message"`
+	// Configuration is the configuration of the send request
+	Configuration *MessageSendConfiguration `json:"configuration,omitempty"`
+	// Metadata is the metadata associated with the message
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// NewMessage creates a new Message with a generated message ID
+func NewMessage(taskID, contextID string, parts []Part) *Message {
+	return &Message{
+		MessageID: util.GenerateUUID(),
+		Kind:      KindMessage,
+		TaskID:    taskID,
+		ContextID: contextID,
+		Parts:     parts,
+		Metadata:  make(map[string]interface{}),
+	}
+}
+
+// NewUserMessage creates a new Message sent by the client
+func NewUserMessage(taskID, contextID string, parts []Part) *Message {
+	return NewMessage(taskID, contextID, parts).WithRole(RoleUser)
+}
+
+// NewAgentMessage creates a new Message sent by the agent
+func NewAgentMessage(taskID, contextID string, parts []Part) *Message {
+	return NewMessage(taskID, contextID, parts).WithRole(RoleAgent)
+}
+
+// AddPart adds a part to the message
+func (m *Message) AddPart(part Part) {
+	m.Parts = append(m.Parts, part)
+}
+
+// GetParts returns the message parts
+func (m *Message) GetParts() []Part {
+	return m.Parts
+}
+
+// SetParts sets the message parts
+func (m *Message) SetParts(parts []Part) {
+	m.Parts = parts
+}
+
+// GetTaskID returns the task ID
+func (m *Message) GetTaskID() string {
+	return m.TaskID
+}
+
+// SetTaskID sets the task ID
+func (m *Message) SetTaskID(taskID string) {
+	m.TaskID = taskID
+}
+
+// GetContextID returns the context ID
+func (m *Message) GetContextID() string {
+	return m.ContextID
+}
+
+// SetContextID sets the context ID
+func (m *Message) SetContextID(contextID string) {
+	m.ContextID = contextID
+}
+
+// GetMessageID returns the message ID
+func (m *Message) GetMessageID() string {
+	return m.MessageID
+}
+
+// SetMessageID sets the message ID
+func (m *Message) SetMessageID(messageID string) {
+	m.MessageID = messageID
+}
+
+// GetRole returns the sender of the message
+func (m *Message) GetRole() Role {
+	return m.Role
+}
+
+// SetRole sets the sender of the message
+func (m *Message) SetRole(role Role) {
+	m.Role = role
+}
+
+// GetReferenceTaskIDs returns the IDs of the tasks the message refers to
+func (m *Message) GetReferenceTaskIDs() []string {
+	return m.ReferenceTaskIDs
+}
+
+// SetReferenceTaskIDs sets the IDs of the tasks the message refers to
+func (m *Message) SetReferenceTaskIDs(taskIDs []string) {
+	m.ReferenceTaskIDs = taskIDs
+}
+
+// GetExtensions returns the URIs of the extensions that contributed to the message
+func (m *Message) GetExtensions() []string {
+	return m.Extensions
+}
+
+// SetExtensions sets the URIs of the extensions that contributed to the message
+func (m *Message) SetExtensions(extensions []string) {
+	m.Extensions = extensions
+}
+
+// WithMessageID sets the message ID for the message
+func (m *Message) WithMessageID(messageID string) *Message {
+	m.MessageID = messageID
+	return m
+}
+
+// WithRole sets the sender of the message
+func (m *Message) WithRole(role Role) *Message {
+	m.Role = role
+	return m
+}
+
+// WithReferenceTaskIDs sets the IDs of the tasks the message refers to
+func (m *Message) WithReferenceTaskIDs(taskIDs ...string) *Message {
+	m.ReferenceTaskIDs = taskIDs
+	return m
+}
+
+// WithExtensions sets the URIs of the extensions that contributed to the message
+func (m *Message) WithExtensions(extensions ...string) *Message {
+	m.Extensions = extensions
+	return m
+}
+
+// WithTaskID sets the task ID for the message
+func (m *Message) WithTaskID(taskID string) *Message {
+	m.TaskID = taskID
+	return m
+}
+
+// WithContextID sets the context ID for the message
+func (m *Message) WithContextID(contextID string) *Message {
+	m.ContextID = contextID
+	return m
+}
+
+// WithParts sets the parts for the message
+func (m *Message) WithParts(parts []Part) *Message {
+	m.Parts = parts
+	return m
+}
+
+// WithMetadata sets the metadata for the message
+func (m *Message) WithMetadata(metadata map[string]interface{}) *Message {
+	m.Metadata = metadata
+	return m
+}
+
+// WithKind sets the kind for the message
+func (m *Message) WithKind(kind string) *Message {
+	m.Kind = kind
+	return m
+}
+
+// SetMetadata sets metadata for the message
+func (m *Message) SetMetadata(key string, value interface{}) {
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]interface{})
+	}
+	m.Metadata[key] = value
+}
+
+// GetMetadata gets metadata from the message
+func (m *Message) GetMetadata(key string) interface{} {
+	if m.Metadata == nil {
+		return nil
+	}
+	return m.Metadata[key]
+}
+
+// Validate checks that the message carries everything the A2A specification requires:
+// a message ID, a role, the "message" kind and at least one part.
+// Roles other than user and agent are already rejected when the message is decoded.
+func (m *Message) Validate() error {
+	if m.MessageID == "" {
+		return errors.New("message.messageId is required")
+	}
+	if m.Role == "" {
+		return errors.New("message.role is required")
+	}
+	if m.Kind != "" && m.Kind != KindMessage {
+		return fmt.Errorf("message.kind %q is not %q", m.Kind, KindMessage)
+	}
+	if len(m.Parts) == 0 {
+		return errors.New("message.parts must not be empty")
+	}
+	for i, part := range m.Parts {
+		if part == nil {
+			return fmt.Errorf("message.parts[%d] is null", i)
+		}
+	}
+	for i, taskID := range m.ReferenceTaskIDs {
+		if taskID == "" {
+			return fmt.Errorf("message.referenceTaskIds[%d] is empty", i)
+		}
+	}
+	return nil
+}
+
+// IsSendStreamingMessageResponse implements the SendStreamingMessageResponse interface
+func (m *Message) IsSendStreamingMessageResponse() {}
+
+// IsSendMessageResponse implements the SendMessageResponse interface
+func (m *Message) IsSendMessageResponse() {}
+
+// IsTaskUpdate implements the TaskUpdate interface, a message produced during a task joins its history
+func (m *Message) IsTaskUpdate() {}
+
+// MarshalJSON implements the json.Marshaler interface
+func (m *Message) MarshalJSON() ([]byte, error) {
+	type Alias Message
+	aux := &struct {
+		*Alias
+		Kind string `json:"kind"`
+	}{
+		Alias: (*Alias)(m),
+		Kind:  m.Kind,
+	}
+	if aux.Kind == "" {
+		aux.Kind = KindMessage
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Parts are decoded into the concrete type registered for their kind.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type Alias Message
+	aux := &struct {
+		*Alias
+		Parts []json.RawMessage `json:"parts"`
+	}{
+		Alias: (*Alias)(m),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	parts, err := UnmarshalParts(aux.Parts)
+	if err != nil {
+		return err
+	}
+	m.Parts = parts
+	return nil
+}
+
+// Request metadata keys understood by the server, set on the message or the send params
+const (
+	// SkillIDMetadataKey selects the agent skill the message is meant for
+	SkillIDMetadataKey = "skillId"
+	// DeadlineMetadataKey carries the RFC 3339 time by which the client needs the task finished
+	DeadlineMetadataKey = "deadline"
+	// PriorityMetadataKey carries the admission priority of the task, higher runs first when the server is busy
+	PriorityMetadataKey = "priority"
+)
+
+// NewMessageSendParams creates a new MessageSendParams
+func NewMessageSendParams(message *Message, metadata map[string]interface{}) *MessageSendParams {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	return &MessageSendParams{
+		Message:  message,
+		Metadata: metadata,
+	}
+}
+
+// GetMessage returns the message
+func (p *MessageSendParams) GetMessage() *Message {
+	return p.Message
+}
+
+// SetMessage sets the message
+func (p *MessageSendParams) SetMessage(message *Message) {
+	p.Message = message
+}
+
+// GetConfiguration returns the configuration
+func (p *MessageSendParams) GetConfiguration() *MessageSendConfiguration {
+	return p.Configuration
+}
+
+// SetConfiguration sets the configuration
+func (p *MessageSendParams) SetConfiguration(configuration *MessageSendConfiguration) {
+	p.Configuration = configuration
+}
+
+// Validate checks that the params carry a valid message sent by the client
+func (p *MessageSendParams) Validate() error {
+	if p.Message == nil {
+		return errors.New("message is required")
+	}
+	if err := p.Message.Validate(); err != nil {
+		return err
+	}
+	if p.Message.Role != RoleUser {
+		return fmt.Errorf("message.role must be %q", RoleUser)
+	}
+	if p.Configuration != nil {
+		return validateHistoryLength(p.Configuration.HistoryLength)
+	}
+	return nil
+}
+
+// IsBlocking reports whether the client waits for the task to finish, which is the default
+// unless the configuration sets blocking to false
+func (p *MessageSendParams) IsBlocking() bool {
+	if p.Configuration == nil || p.Configuration.Blocking == nil {
+		return true
+	}
+	return *p.Configuration.Blocking
+}
+
+// GetMetadata returns the metadata
+func (p *MessageSendParams) GetMetadata() map[string]interface{} {
+	return p.Metadata
+}
+
+// SetMetadata sets the metadata
+func (p *MessageSendParams) SetMetadata(metadata map[string]interface{}) {
+	p.Metadata = metadata
+}
+
+// SetSkillID selects the agent skill the message is meant for
+func (p *MessageSendParams) SetSkillID(skillID string) {
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]interface{})
+	}
+	p.Metadata[SkillIDMetadataKey] = skillID
+}
+
+// SetDeadline sets the time by which the client needs the task finished, the task fails once it passed
+func (p *MessageSendParams) SetDeadline(deadline time.Time) {
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]interface{})
+	}
+	p.Metadata[DeadlineMetadataKey] = deadline.Format(time.RFC3339Nano)
+}
+
+// SetPriority sets the admission priority of the task, higher runs first when the server is busy
+func (p *MessageSendParams) SetPriority(priority int) {
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]interface{})
+	}
+	p.Metadata[PriorityMetadataKey] = priority
+}
+
+// GetPriority returns the admission priority set on the message or the params, zero if none
+func (p *MessageSendParams) GetPriority() int {
+	for _, metadata := range []map[string]interface{}{p.messageMetadata(), p.Metadata} {
+		switch priority := metadata[PriorityMetadataKey].(type) {
+		case int:
+			return priority
+		case float64:
+			return int(priority)
+		}
+	}
+	return 0
+}
+
+// messageMetadata returns the metadata of the message, nil without a message
+func (p *MessageSendParams) messageMetadata() map[string]interface{} {
+	if p.Message == nil {
+		return nil
+	}
+	return p.Message.Metadata
+}
@@ -1,11 +1,13 @@
 package model
 
+import "fmt"
+
 // MessageSendConfiguration represents configuration for sending messages in the A2A4J framework.
 type MessageSendConfiguration struct {
 	// AcceptedOutputModes are the accepted output modalities by the client
 	AcceptedOutputModes []string `json:"acceptedOutputModes,omitempty"`
 
-	// HistoryLength is the number of recent messages to be retrieved
+	// HistoryLength is the number of recent messages to be retrieved, the whole history when unset
 	HistoryLength *int `json:"historyLength,omitempty"`
 
 	// PushNotificationConfig is where the server should send notifications when disconnected
@@ -15,6 +17,14 @@ type MessageSendConfiguration struct {
 	Blocking *bool `json:"blocking,omitempty"`
 }
 
+// validateHistoryLength checks that a requested history length is not negative
+func validateHistoryLength(historyLength *int) error {
+	if historyLength != nil && *historyLength < 0 {
+		return fmt.Errorf("historyLength must not be negative, got %d", *historyLength)
+	}
+	return nil
+}
+
 // NewMessageSendConfiguration creates a new MessageSendConfiguration
 func NewMessageSendConfiguration(acceptedOutputModes []string, historyLength *int,
 	pushNotificationConfig *PushNotificationConfig, blocking *bool) *MessageSendConfiguration {
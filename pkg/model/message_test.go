@@ -0,0 +1,50 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+func TestMessageSendParamsValidate(t *testing.T) {
+	historyLength := func(n int) *model.MessageSendConfiguration {
+		return &model.MessageSendConfiguration{HistoryLength: &n}
+	}
+	tests := []struct {
+		name    string
+		mutate  func(params *model.MessageSendParams)
+		wantErr bool
+	}{
+		{"valid message", func(params *model.MessageSendParams) {}, false},
+		{"missing message", func(params *model.MessageSendParams) { params.Message = nil }, true},
+		{"missing message id", func(params *model.MessageSendParams) { params.Message.MessageID = "" }, true},
+		{"missing role", func(params *model.MessageSendParams) { params.Message.Role = "" }, true},
+		{"agent role", func(params *model.MessageSendParams) { params.Message.Role = model.RoleAgent }, true},
+		{"wrong kind", func(params *model.MessageSendParams) { params.Message.Kind = "task" }, true},
+		{"no parts", func(params *model.MessageSendParams) { params.Message.Parts = nil }, true},
+		{"null part", func(params *model.MessageSendParams) { params.Message.Parts = []model.Part{nil} }, true},
+		{"empty reference task", func(params *model.MessageSendParams) { params.Message.WithReferenceTaskIDs("") }, true},
+		{"history length", func(params *model.MessageSendParams) { params.Configuration = historyLength(2) }, false},
+		{"negative history length", func(params *model.MessageSendParams) { params.Configuration = historyLength(-1) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.NewMessageSendParams(model.NewUserMessage("task-1", "", []model.Part{model.NewTextPart("hello")}), nil)
+			tt.mutate(params)
+			if err := params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoleRejectsUnknownValues(t *testing.T) {
+	var message model.Message
+	if err := json.Unmarshal([]byte(`{"kind":"message","messageId":"m-1","role":"system","parts":[]}`), &message); err == nil {
+		t.Errorf("Unmarshal() role = %q, want an error for an unknown role", message.Role)
+	}
+	if err := json.Unmarshal([]byte(`{"kind":"message","messageId":"m-1","role":"agent","parts":[]}`), &message); err != nil || message.Role != model.RoleAgent {
+		t.Errorf("Unmarshal() = %q, %v, want the agent role", message.Role, err)
+	}
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"time"
+)
+
+// RequestContext represents a request context in the system
+type RequestContext struct {
+	// TaskID is the ID of the task
+	TaskID string `json:"taskId"`
+
+	// ContextID is the ID of the context
+	ContextID string `json:"contextId"`
+
+	// Task is the task associated with this context
+	Task *Task `json:"task"`
+
+	// RelatedTasks are tasks related to this context
+	RelatedTasks []*Task `json:"relatedTasks,omitempty"`
+
+	// ReferenceTasks are the tasks listed in the referenceTaskIds of the incoming message
+	ReferenceTasks []*Task `json:"referenceTasks,omitempty"`
+
+	// Message is the incoming message that triggered the execution
+	Message *Message `json:"message,omitempty"`
+
+	// Configuration is the send configuration of the incoming message
+	Configuration *MessageSendConfiguration `json:"configuration,omitempty"`
+
+	// Metadata is the metadata of the request
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Extensions are the URIs of the protocol extensions requested by the client
+	Extensions []string `json:"extensions,omitempty"`
+
+	// User is the authenticated caller, nil for an anonymous request
+	User *User `json:"user,omitempty"`
+}
+
+// NewRequestContext creates a new RequestContext
+func NewRequestContext(taskID, contextID string, task *Task) *RequestContext {
+	return &RequestContext{
+		TaskID:         taskID,
+		ContextID:      contextID,
+		Task:           task,
+		RelatedTasks:   make([]*Task, 0),
+		ReferenceTasks: make([]*Task, 0),
+	}
+}
+
+// GetTaskID returns the ID of the task
+func (c *RequestContext) GetTaskID() string {
+	return c.TaskID
+}
+
+// GetContextID returns the ID of the context
+func (c *RequestContext) GetContextID() string {
+	return c.ContextID
+}
+
+// GetTask returns the task associated with this context
+func (c *RequestContext) GetTask() *Task {
+	return c.Task
+}
+
+// GetRelatedTasks returns the tasks related to this context
+func (c *RequestContext) GetRelatedTasks() []*Task {
+	return c.RelatedTasks
+}
+
+// GetReferenceTasks returns the tasks the incoming message refers to
+func (c *RequestContext) GetReferenceTasks() []*Task {
+	return c.ReferenceTasks
+}
+
+// GetMessage returns the incoming message
+func (c *RequestContext) GetMessage() *Message {
+	return c.Message
+}
+
+// GetConfiguration returns the send configuration of the incoming message
+func (c *RequestContext) GetConfiguration() *MessageSendConfiguration {
+	return c.Configuration
+}
+
+// GetAcceptedOutputModes returns the output modes accepted by the client, nil when it accepts any
+func (c *RequestContext) GetAcceptedOutputModes() []string {
+	if c.Configuration == nil {
+		return nil
+	}
+	return c.Configuration.AcceptedOutputModes
+}
+
+// GetHistoryLength returns the number of most recent history messages the client wants back, nil for all
+func (c *RequestContext) GetHistoryLength() *int {
+	if c.Configuration == nil {
+		return nil
+	}
+	return c.Configuration.HistoryLength
+}
+
+// GetMetadata returns the metadata of the request
+func (c *RequestContext) GetMetadata() map[string]interface{} {
+	return c.Metadata
+}
+
+// GetExtensions returns the URIs of the protocol extensions requested by the client
+func (c *RequestContext) GetExtensions() []string {
+	return c.Extensions
+}
+
+// GetUser returns the authenticated caller, nil for an anonymous request
+func (c *RequestContext) GetUser() *User {
+	return c.User
+}
+
+// GetSkillID returns the agent skill selected by the message or the request metadata, empty if none
+func (c *RequestContext) GetSkillID() string {
+	skillID, _ := c.metadataValue(SkillIDMetadataKey).(string)
+	return skillID
+}
+
+// GetDeadline returns the deadline supplied in the message or the request metadata
+func (c *RequestContext) GetDeadline() (time.Time, bool) {
+	value, ok := c.metadataValue(DeadlineMetadataKey).(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	deadline, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return deadline, true
+}
+
+// metadataValue returns the value of a metadata key, from the message first and then from the request
+func (c *RequestContext) metadataValue(key string) interface{} {
+	if c.Message != nil {
+		if value, ok := c.Message.Metadata[key]; ok {
+			return value
+		}
+	}
+	return c.Metadata[key]
+}
@@ -11,24 +11,29 @@ type Task struct {
 	ID string `json:"id"`
 	// ContextID is the ID of the context this task belongs to
 	ContextID string `json:"contextId,omitempty"`
+	// Kind is the kind of the object, always "task"
+	Kind string `json:"kind"`
 	// Status is the current status of the task
 	Status *TaskStatus `json:"status"`
 	// Artifacts is the list of artifacts associated with the task
-	Artifacts []*TaskArtifact `json:"artifacts"`
+	Artifacts []*Artifact `json:"artifacts"`
 	// History is the list of messages exchanged within this task
 	History []*Message `json:"history"`
 	// Metadata is the metadata associated with the task
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 	// CreatedAt is the creation time of the task
 	CreatedAt string `json:"createdAt,omitempty"`
+	// StatusHistory is the list of status transitions of the task, oldest first
+	StatusHistory []*TaskStatusTransition `json:"statusHistory,omitempty"`
 }
 
 // NewTask creates a new Task
 func NewTask(id string) *Task {
 	return &Task{
 		ID:        id,
+		Kind:      KindTask,
 		CreatedAt: time.Now().Format(time.RFC3339),
-		Artifacts: make([]*TaskArtifact, 0),
+		Artifacts: make([]*Artifact, 0),
 		History:   make([]*Message, 0),
 		Metadata:  make(map[string]interface{}),
 	}
@@ -60,12 +65,12 @@ func (t *Task) SetStatus(status *TaskStatus) {
 }
 
 // GetArtifacts returns the artifacts of the task
-func (t *Task) GetArtifacts() []*TaskArtifact {
+func (t *Task) GetArtifacts() []*Artifact {
 	return t.Artifacts
 }
 
 // SetArtifacts sets the artifacts of the task
-func (t *Task) SetArtifacts(artifacts []*TaskArtifact) {
+func (t *Task) SetArtifacts(artifacts []*Artifact) {
 	t.Artifacts = artifacts
 }
 
@@ -79,6 +84,44 @@ func (t *Task) SetHistory(history []*Message) {
 	t.History = history
 }
 
+// GetStatusHistory returns the status transitions of the task
+func (t *Task) GetStatusHistory() []*TaskStatusTransition {
+	return t.StatusHistory
+}
+
+// SetStatusHistory sets the status transitions of the task
+func (t *Task) SetStatusHistory(statusHistory []*TaskStatusTransition) {
+	t.StatusHistory = statusHistory
+}
+
+// Clone returns a copy of the task whose history, artifacts, status history and metadata can be changed
+// without affecting the task. Messages, artifacts and statuses are shared, an update replaces them.
+func (t *Task) Clone() *Task {
+	clone := *t
+	clone.Artifacts = append(make([]*Artifact, 0, len(t.Artifacts)), t.Artifacts...)
+	clone.History = append(make([]*Message, 0, len(t.History)), t.History...)
+	if t.StatusHistory != nil {
+		clone.StatusHistory = append(make([]*TaskStatusTransition, 0, len(t.StatusHistory)), t.StatusHistory...)
+	}
+	if t.Metadata != nil {
+		clone.Metadata = make(map[string]interface{}, len(t.Metadata))
+		for key, value := range t.Metadata {
+			clone.Metadata[key] = value
+		}
+	}
+	return &clone
+}
+
+// RecordStatus sets the status of the task and appends the change to its status history
+func (t *Task) RecordStatus(status *TaskStatus, reason string) {
+	var from TaskState
+	if t.Status != nil {
+		from = t.Status.State
+	}
+	t.Status = status
+	t.StatusHistory = append(t.StatusHistory, NewTaskStatusTransition(from, status, reason))
+}
+
 // GetMetadata returns the metadata of the task
 func (t *Task) GetMetadata() map[string]interface{} {
 	return t.Metadata
@@ -99,21 +142,47 @@ func (t *Task) IsSendMessageResponse() {}
 func (t *Task) IsSendStreamingMessageResponse() {}
 
 // AddArtifact adds an artifact to the task
-func (t *Task) AddArtifact(artifact *TaskArtifact) {
+func (t *Task) AddArtifact(artifact *Artifact) {
 	if t.Artifacts == nil {
-		t.Artifacts = make([]*TaskArtifact, 0)
+		t.Artifacts = make([]*Artifact, 0)
 	}
 	t.Artifacts = append(t.Artifacts, artifact)
 }
 
+// RecentHistory returns a copy of the last historyLength messages of the history,
+// the whole history when historyLength is nil
+func (t *Task) RecentHistory(historyLength *int) []*Message {
+	history := t.History
+	if historyLength != nil && *historyLength < len(history) {
+		history = history[len(history)-max(*historyLength, 0):]
+	}
+	return append(make([]*Message, 0, len(history)), history...)
+}
+
+// GetArtifact returns the artifact of the task with the given ID, nil if there is none
+func (t *Task) GetArtifact(artifactID string) *Artifact {
+	for _, artifact := range t.Artifacts {
+		if artifact.ArtifactID == artifactID {
+			return artifact
+		}
+	}
+	return nil
+}
+
 // MarshalJSON implements the json.Marshaler interface
 func (t *Task) MarshalJSON() ([]byte, error) {
 	type Alias Task
-	return json.Marshal(&struct {
+	aux := &struct {
 		*Alias
+		Kind string `json:"kind"`
 	}{
 		Alias: (*Alias)(t),
-	})
+		Kind:  t.Kind,
+	}
+	if aux.Kind == "" {
+		aux.Kind = KindTask
+	}
+	return json.Marshal(aux)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface
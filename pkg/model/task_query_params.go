@@ -0,0 +1,97 @@
+package model
+
+import "encoding/json"
+
+// TaskQueryParams represents parameters for querying tasks.
+type TaskQueryParams struct {
+	// TaskID is the ID of the task, sent as "id" as the A2A specification requires.
+	// The legacy "taskId" field is still accepted when decoding.
+	TaskID string `json:"id"`
+
+	// HistoryLength is the number of most recent history messages to return, the whole history when unset.
+	HistoryLength *int `json:"historyLength,omitempty"`
+
+	// Offset is the sequence id of the last event received by a resubscribing client.
+	// Retained events after it are replayed before live delivery, zero replays every retained event.
+	Offset *uint64 `json:"offset,omitempty"`
+}
+
+// NewTaskQueryParams creates a new TaskQueryParams with the given task ID.
+func NewTaskQueryParams(taskID string) *TaskQueryParams {
+	return &TaskQueryParams{
+		TaskID: taskID,
+	}
+}
+
+// NewTaskQueryParamsWithHistory creates a new TaskQueryParams returning at most historyLength history messages.
+func NewTaskQueryParamsWithHistory(taskID string, historyLength int) *TaskQueryParams {
+	return &TaskQueryParams{
+		TaskID:        taskID,
+		HistoryLength: &historyLength,
+	}
+}
+
+// NewTaskQueryParamsWithOffset creates a new TaskQueryParams resuming after the given event sequence id.
+func NewTaskQueryParamsWithOffset(taskID string, offset uint64) *TaskQueryParams {
+	return &TaskQueryParams{
+		TaskID: taskID,
+		Offset: &offset,
+	}
+}
+
+// GetTaskID returns the task ID.
+func (p *TaskQueryParams) GetTaskID() string {
+	return p.TaskID
+}
+
+// SetTaskID sets the task ID.
+func (p *TaskQueryParams) SetTaskID(taskID string) {
+	p.TaskID = taskID
+}
+
+// GetHistoryLength returns the number of most recent history messages to return, nil for the whole history.
+func (p *TaskQueryParams) GetHistoryLength() *int {
+	return p.HistoryLength
+}
+
+// SetHistoryLength sets the number of most recent history messages to return.
+func (p *TaskQueryParams) SetHistoryLength(historyLength int) {
+	p.HistoryLength = &historyLength
+}
+
+// Validate checks that the history length, when set, is not negative.
+func (p *TaskQueryParams) Validate() error {
+	return validateHistoryLength(p.HistoryLength)
+}
+
+// GetOffset returns the event sequence id to resume after and whether it is set.
+func (p *TaskQueryParams) GetOffset() (uint64, bool) {
+	if p.Offset == nil {
+		return 0, false
+	}
+	return *p.Offset, true
+}
+
+// SetOffset sets the event sequence id to resume after.
+func (p *TaskQueryParams) SetOffset(offset uint64) {
+	p.Offset = &offset
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The task ID is read from "id", or from the legacy "taskId" field when "id" is missing.
+func (p *TaskQueryParams) UnmarshalJSON(data []byte) error {
+	type Alias TaskQueryParams
+	aux := &struct {
+		*Alias
+		LegacyTaskID string `json:"taskId"`
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if p.TaskID == "" {
+		p.TaskID = aux.LegacyTaskID
+	}
+	return nil
+}
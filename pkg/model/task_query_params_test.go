@@ -0,0 +1,41 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+func TestTaskQueryParamsAcceptsLegacyTaskID(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"id", `{"id":"task-1"}`, "task-1"},
+		{"legacy taskId", `{"taskId":"task-1"}`, "task-1"},
+		{"id wins over taskId", `{"id":"task-1","taskId":"task-2"}`, "task-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params model.TaskQueryParams
+			if err := json.Unmarshal([]byte(tt.data), &params); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if params.TaskID != tt.want {
+				t.Errorf("TaskID = %q, want %q", params.TaskID, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskQueryParamsMarshalsID(t *testing.T) {
+	data, err := json.Marshal(model.NewTaskQueryParamsWithHistory("task-1", 2))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got := string(data); got != `{"id":"task-1","historyLength":2}` {
+		t.Errorf("Marshal() = %s, want the id and historyLength fields", got)
+	}
+}
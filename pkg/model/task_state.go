@@ -45,6 +45,52 @@ func (s TaskState) String() string {
 	return string(s)
 }
 
+// IsFinal reports whether the state is terminal: completed, failed, canceled or rejected
+func (s TaskState) IsFinal() bool {
+	switch s {
+	case TaskStateCompleted, TaskStateFailed, TaskStateCanceled, TaskStateRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// taskStateTransitions lists the states each non-final state may move to
+var taskStateTransitions = map[TaskState][]TaskState{
+	TaskStateSubmitted: {
+		TaskStateSubmitted, TaskStateWorking, TaskStateInputRequired, TaskStateAuthRequired,
+		TaskStateCompleted, TaskStateFailed, TaskStateCanceled, TaskStateRejected,
+	},
+	TaskStateWorking: {
+		TaskStateWorking, TaskStateInputRequired, TaskStateAuthRequired,
+		TaskStateCompleted, TaskStateFailed, TaskStateCanceled,
+	},
+	TaskStateInputRequired: {
+		TaskStateInputRequired, TaskStateWorking, TaskStateCompleted, TaskStateFailed, TaskStateCanceled,
+	},
+	TaskStateAuthRequired: {
+		TaskStateAuthRequired, TaskStateWorking, TaskStateFailed, TaskStateCanceled, TaskStateRejected,
+	},
+}
+
+// CanTransitionTo reports whether a task in this state may move to next.
+// Final states allow no transition; an empty or unknown state may move anywhere.
+func (s TaskState) CanTransitionTo(next TaskState) bool {
+	if s.IsFinal() {
+		return false
+	}
+	allowed, ok := taskStateTransitions[s]
+	if !ok {
+		return true
+	}
+	for _, state := range allowed {
+		if state == next {
+			return true
+		}
+	}
+	return false
+}
+
 // MarshalJSON implements custom JSON marshaling
 func (s TaskState) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(s))
@@ -0,0 +1,67 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+var allTaskStates = []model.TaskState{
+	model.TaskStateSubmitted, model.TaskStateWorking, model.TaskStateInputRequired, model.TaskStateAuthRequired,
+	model.TaskStateCompleted, model.TaskStateFailed, model.TaskStateCanceled, model.TaskStateRejected,
+}
+
+func TestTaskStateTransitions(t *testing.T) {
+	allowed := map[model.TaskState][]model.TaskState{
+		model.TaskStateSubmitted:     allTaskStates,
+		model.TaskStateWorking:       {model.TaskStateWorking, model.TaskStateInputRequired, model.TaskStateAuthRequired, model.TaskStateCompleted, model.TaskStateFailed, model.TaskStateCanceled},
+		model.TaskStateInputRequired: {model.TaskStateInputRequired, model.TaskStateWorking, model.TaskStateCompleted, model.TaskStateFailed, model.TaskStateCanceled},
+		model.TaskStateAuthRequired:  {model.TaskStateAuthRequired, model.TaskStateWorking, model.TaskStateFailed, model.TaskStateCanceled, model.TaskStateRejected},
+	}
+	for _, from := range allTaskStates {
+		want := make(map[model.TaskState]bool)
+		for _, to := range allowed[from] {
+			want[to] = true
+		}
+		for _, to := range allTaskStates {
+			if got := from.CanTransitionTo(to); got != want[to] {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want[to])
+			}
+		}
+	}
+}
+
+func TestUnknownTaskStateMayMoveAnywhere(t *testing.T) {
+	for _, from := range []model.TaskState{"", model.TaskStateUnknown} {
+		for _, to := range allTaskStates {
+			if !from.CanTransitionTo(to) {
+				t.Errorf("%q.CanTransitionTo(%s) = false, want true", from, to)
+			}
+		}
+	}
+}
+
+func TestTaskStateIsFinal(t *testing.T) {
+	final := map[model.TaskState]bool{
+		model.TaskStateCompleted: true,
+		model.TaskStateFailed:    true,
+		model.TaskStateCanceled:  true,
+		model.TaskStateRejected:  true,
+	}
+	for _, state := range append(allTaskStates, model.TaskStateUnknown) {
+		if got := state.IsFinal(); got != final[state] {
+			t.Errorf("%s.IsFinal() = %v, want %v", state, got, final[state])
+		}
+	}
+}
+
+func TestTaskStateUnmarshalRejectsUnknownValues(t *testing.T) {
+	var state model.TaskState
+	if err := json.Unmarshal([]byte(`"paused"`), &state); err == nil {
+		t.Errorf("Unmarshal() = %q, want an error for an unknown state", state)
+	}
+	if err := json.Unmarshal([]byte(`"input-required"`), &state); err != nil || state != model.TaskStateInputRequired {
+		t.Errorf("Unmarshal() = %q, %v, want input-required", state, err)
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+)
+
+// StatusReasonKey is the status or event metadata key carrying the reason of a status change
+const StatusReasonKey = "reason"
+
+// StatusReasonTimeout is the reason of a task that failed because its execution deadline passed
+const StatusReasonTimeout = "timeout"
+
+// TaskStatusTransition records a single status change of a task
+type TaskStatusTransition struct {
+	// From is the state the task left, empty for the initial status
+	From TaskState `json:"from,omitempty"`
+	// State is the state the task entered
+	State TaskState `json:"state"`
+	// Timestamp is the time of the status change
+	Timestamp string `json:"timestamp"`
+	// Message is the agent message attached to the status, if any
+	Message *Message `json:"message,omitempty"`
+	// Reason explains why the status changed, if known
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewTaskStatusTransition creates a new TaskStatusTransition from the previous state to the given status
+func NewTaskStatusTransition(from TaskState, status *TaskStatus, reason string) *TaskStatusTransition {
+	timestamp := status.Timestamp
+	if timestamp == "" {
+		timestamp = time.Now().Format(time.RFC3339)
+	}
+	return &TaskStatusTransition{
+		From:      from,
+		State:     status.State,
+		Timestamp: timestamp,
+		Message:   status.Message,
+		Reason:    reason,
+	}
+}
+
+// GetFrom returns the state the task left
+func (t *TaskStatusTransition) GetFrom() TaskState {
+	return t.From
+}
+
+// GetState returns the state the task entered
+func (t *TaskStatusTransition) GetState() TaskState {
+	return t.State
+}
+
+// GetTimestamp returns the time of the status change
+func (t *TaskStatusTransition) GetTimestamp() string {
+	return t.Timestamp
+}
+
+// GetMessage returns the agent message attached to the status
+func (t *TaskStatusTransition) GetMessage() *Message {
+	return t.Message
+}
+
+// GetReason returns why the status changed
+func (t *TaskStatusTransition) GetReason() string {
+	return t.Reason
+}
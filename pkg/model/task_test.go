@@ -0,0 +1,88 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// newTaskWithHistory creates a task whose history holds one message per text
+func newTaskWithHistory(texts ...string) *model.Task {
+	task := model.NewTask("task-1")
+	for _, text := range texts {
+		task.History = append(task.History, model.NewUserMessage(task.ID, "", []model.Part{model.NewTextPart(text)}))
+	}
+	return task
+}
+
+func TestTaskCloneSharesNoSlices(t *testing.T) {
+	task := newTaskWithHistory("a")
+	task.RecordStatus(model.NewTaskStatus(model.TaskStateSubmitted), "")
+	task.AddArtifact(model.NewArtifact("result", "Result"))
+	task.Metadata = map[string]interface{}{"key": "value"}
+
+	clone := task.Clone()
+	clone.History = append(clone.History[:0], nil)
+	clone.Artifacts[0] = nil
+	clone.RecordStatus(model.NewTaskStatus(model.TaskStateWorking), "")
+	clone.Metadata["key"] = "changed"
+
+	if task.History[0] == nil || task.Artifacts[0] == nil {
+		t.Error("changing the clone changed the history or artifacts of the task")
+	}
+	if task.Status.State != model.TaskStateSubmitted || len(task.StatusHistory) != 1 {
+		t.Errorf("task status = %s with %d transitions, want submitted with 1", task.Status.State, len(task.StatusHistory))
+	}
+	if task.Metadata["key"] != "value" {
+		t.Errorf("task metadata = %v, want the original value", task.Metadata)
+	}
+}
+
+func TestTaskGetArtifact(t *testing.T) {
+	task := model.NewTask("task-1")
+	task.AddArtifact(model.NewArtifact("a", "A"))
+	task.AddArtifact(model.NewArtifact("b", "B"))
+
+	if artifact := task.GetArtifact("b"); artifact == nil || artifact.Name != "B" {
+		t.Errorf("GetArtifact(b) = %+v, want artifact B", artifact)
+	}
+	if artifact := task.GetArtifact("missing"); artifact != nil {
+		t.Errorf("GetArtifact(missing) = %+v, want nil", artifact)
+	}
+}
+
+func TestTaskRecentHistory(t *testing.T) {
+	length := func(n int) *int { return &n }
+	tests := []struct {
+		name          string
+		historyLength *int
+		want          []string
+	}{
+		{"whole history without a length", nil, []string{"a", "b", "c"}},
+		{"no history for zero", length(0), []string{}},
+		{"last messages", length(2), []string{"b", "c"}},
+		{"whole history for a longer length", length(5), []string{"a", "b", "c"}},
+		{"no history for a negative length", length(-1), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTaskWithHistory("a", "b", "c")
+			history := task.RecentHistory(tt.historyLength)
+
+			if len(history) != len(tt.want) {
+				t.Fatalf("RecentHistory() = %d messages, want %v", len(history), tt.want)
+			}
+			for i, message := range history {
+				if text := message.Parts[0].(*model.TextPart).Text; text != tt.want[i] {
+					t.Errorf("RecentHistory()[%d] = %s, want %s", i, text, tt.want[i])
+				}
+			}
+			if len(history) > 0 {
+				history[0] = nil
+				if task.History[len(task.History)-len(history)] == nil {
+					t.Error("RecentHistory() shares its slice with the task history")
+				}
+			}
+		})
+	}
+}
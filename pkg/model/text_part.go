@@ -52,9 +52,9 @@ func (p *TextPart) MarshalJSON() ([]byte, error) {
 		Type     PartType               `json:"type"`
 		Text     string                 `json:"text"`
 	}{
-		Kind:     p.Kind,
+		Kind:     string(PartTypeText),
 		Metadata: p.Metadata,
-		Type:     p.Type,
+		Type:     PartTypeText,
 		Text:     p.Text,
 	}
 	return json.Marshal(aux)
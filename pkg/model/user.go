@@ -0,0 +1,37 @@
+package model
+
+// User represents the authenticated caller of a request
+type User struct {
+	// Name identifies the user
+	Name string `json:"name"`
+
+	// Scopes are the permissions granted to the user
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Claims are the attributes asserted about the user by the authenticator
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+// NewUser creates a new User
+func NewUser(name string, scopes []string, claims map[string]interface{}) *User {
+	return &User{
+		Name:   name,
+		Scopes: scopes,
+		Claims: claims,
+	}
+}
+
+// GetName returns the name of the user
+func (u *User) GetName() string {
+	return u.Name
+}
+
+// GetScopes returns the scopes granted to the user
+func (u *User) GetScopes() []string {
+	return u.Scopes
+}
+
+// GetClaims returns the claims about the user
+func (u *User) GetClaims() map[string]interface{} {
+	return u.Claims
+}
@@ -3,7 +3,7 @@ package client
 import (
 	"context"
 
-	model2 "github.com/a2ap/a2ago/internal/model"
+	model2 "github.com/a2ap/a2ago/pkg/model"
 )
 
 // A2aClient defines the core functionality of an A2A client.
@@ -17,10 +17,16 @@ type A2aClient interface {
 	RetrieveAgentCard() *model2.AgentCard
 
 	// SendMessage sends a task request to the server (non-streaming).
+	// It fails when the agent answers with a direct message instead of a task.
 	SendMessage(ctx context.Context, params *model2.MessageSendParams) (*model2.Task, error)
 
+	// SendMessageResponse sends a message to the server (non-streaming) and returns
+	// the resulting *Task, or the *Message the agent answered with directly.
+	SendMessageResponse(ctx context.Context, params *model2.MessageSendParams) (model2.SendMessageResponse, error)
+
 	// SendMessageStream sends a task request and subscribes to streaming updates.
-	// Returns a channel that emits task update events.
+	// Returns a channel that emits task update events. A failing stream emits a
+	// *StreamError as its last value before the channel is closed.
 	SendMessageStream(ctx context.Context, params *model2.MessageSendParams) (<-chan model2.SendStreamingMessageResponse, error)
 
 	// GetTask retrieves the current state of a task.
@@ -36,7 +42,8 @@ type A2aClient interface {
 	GetTaskPushNotification(ctx context.Context, params *model2.TaskIdParams) (*model2.TaskPushNotificationConfig, error)
 
 	// ResubscribeTask resubscribes to updates for a task after a potential connection interruption.
-	// Returns a channel that emits task update events.
+	// Returns a channel that emits task update events. A failing stream emits a
+	// *StreamError as its last value before the channel is closed.
 	ResubscribeTask(ctx context.Context, params *model2.TaskQueryParams) (<-chan model2.SendStreamingMessageResponse, error)
 
 	// RetrieveAuthenticatedExtendedAgentCard retrieves the authenticated extended AgentCard.
@@ -47,6 +54,3 @@ type A2aClient interface {
 	// This is a client-side heuristic and might not be perfectly accurate.
 	Supports(capability string) bool
 }
-
-// DefaultA2aClient is a default implementation of A2aClient.
-// ... existing code ...
@@ -0,0 +1,492 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2ap/a2ago/internal/util"
+
+	model2 "github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/client"
+
+	"github.com/a2ap/a2ago/pkg/jsonrpc"
+)
+
+// DefaultExtendedCardPath is the path of the authenticated extended card relative to the endpoint host
+const DefaultExtendedCardPath = "/a2a/agent/authenticatedExtendedCard"
+
+// ClientOption configures a DefaultA2aClient
+type ClientOption func(*DefaultA2aClient)
+
+// WithEndpoint overrides the JSON-RPC endpoint instead of using AgentCard.URL
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *DefaultA2aClient) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithExtendedCardURL overrides the URL of the authenticated extended agent card
+func WithExtendedCardURL(extendedCardURL string) ClientOption {
+	return func(c *DefaultA2aClient) {
+		c.extendedCardURL = extendedCardURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for all requests.
+// Streams, which last as long as their context, use a copy without its Timeout.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *DefaultA2aClient) {
+		c.client = httpClient
+	}
+}
+
+// WithExtensions requests the protocol extensions with the given URIs on every JSON-RPC request
+func WithExtensions(uris ...string) ClientOption {
+	return func(c *DefaultA2aClient) {
+		c.extensions = uris
+	}
+}
+
+// DefaultA2aClient is a default implementation of A2aClient.
+// Every JSON-RPC method is posted to the same endpoint, which is AgentCard.URL
+// unless overridden with WithEndpoint.
+type DefaultA2aClient struct {
+	agentCard       *model2.AgentCard
+	cardResolver    client.CardResolver
+	client          *http.Client
+	streamClient    *http.Client // client without timeout for SSE streams
+	endpoint        string
+	extendedCardURL string
+	extensions      []string
+
+	mu           sync.Mutex
+	lastEventIDs map[string]uint64 // last SSE event id received per task
+}
+
+// NewDefaultA2aClient creates a new DefaultA2aClient.
+func NewDefaultA2aClient(cardResolver client.CardResolver, opts ...ClientOption) *DefaultA2aClient {
+	return NewDefaultA2aClientWithCard(nil, cardResolver, opts...)
+}
+
+// NewDefaultA2aClientWithCard creates a new DefaultA2aClient with the given AgentCard.
+func NewDefaultA2aClientWithCard(agentCard *model2.AgentCard, cardResolver client.CardResolver, opts ...ClientOption) *DefaultA2aClient {
+	c := &DefaultA2aClient{
+		agentCard:    agentCard,
+		cardResolver: cardResolver,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		lastEventIDs: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// A stream stays open for as long as the task runs, only its context ends it
+	streamClient := *c.client
+	streamClient.Timeout = 0
+	c.streamClient = &streamClient
+	return c
+}
+
+// AgentCard returns the AgentCard info currently in client.
+func (c *DefaultA2aClient) AgentCard() *model2.AgentCard {
+	if c.agentCard != nil {
+		return c.agentCard
+	}
+	return c.RetrieveAgentCard()
+}
+
+// RetrieveAgentCard retrieves the AgentCard for the server this client connects to.
+func (c *DefaultA2aClient) RetrieveAgentCard() *model2.AgentCard {
+	if c.cardResolver == nil {
+		return c.agentCard
+	}
+	card, _ := c.cardResolver.ResolveCard(context.Background())
+	c.agentCard = card
+	return card
+}
+
+// Endpoint resolves the JSON-RPC endpoint used for every method.
+func (c *DefaultA2aClient) Endpoint() (string, error) {
+	if c.endpoint != "" {
+		return c.endpoint, nil
+	}
+	card := c.AgentCard()
+	if card == nil || card.URL == "" {
+		return "", fmt.Errorf("agent card URL not available")
+	}
+	return card.URL, nil
+}
+
+// unmarshalResult unmarshals a JSON-RPC response result into the target type.
+func unmarshalResult(result interface{}, target interface{}) error {
+	resultData, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error marshaling result: %v", err)
+	}
+	return json.Unmarshal(resultData, target)
+}
+
+// unmarshalStreamingResult decodes a streaming result into the concrete type registered for its kind.
+func unmarshalStreamingResult(result interface{}) (model2.SendStreamingMessageResponse, error) {
+	resultData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling result: %v", err)
+	}
+	return model2.UnmarshalStreamingResponse(resultData)
+}
+
+// post sends a JSON-RPC request to the endpoint and returns the HTTP response.
+func (c *DefaultA2aClient) post(ctx context.Context, method string, params interface{}, accept string) (*http.Response, error) {
+	endpoint, err := c.Endpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonRpcRequest := jsonrpc.NewJSONRPCRequest(method, params, util.GenerateUUID())
+	jsonData, err := json.Marshal(jsonRpcRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling JSON-RPC request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if len(c.extensions) > 0 {
+		req.Header.Set("X-A2A-Extensions", strings.Join(c.extensions, ", "))
+	}
+	httpClient := c.client
+	if accept == "text/event-stream" {
+		httpClient = c.streamClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending HTTP request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("server returned non-200 status: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// call sends a JSON-RPC request and unmarshals its result into target.
+func (c *DefaultA2aClient) call(ctx context.Context, method string, params interface{}, target interface{}) error {
+	resp, err := c.post(ctx, method, params, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var jsonRpcResponse jsonrpc.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&jsonRpcResponse); err != nil {
+		return fmt.Errorf("error decoding JSON-RPC response: %v", err)
+	}
+
+	if jsonRpcResponse.Error != nil {
+		return fmt.Errorf("JSON-RPC error: %w", jsonRpcResponse.Error)
+	}
+
+	if err := unmarshalResult(jsonRpcResponse.Result, target); err != nil {
+		return fmt.Errorf("error unmarshaling result: %v", err)
+	}
+	return nil
+}
+
+// stream sends a streaming JSON-RPC request and forwards every result read from
+// the Server-Sent Events response to the returned channel. A failure ends the
+// stream with a *client.StreamError.
+func (c *DefaultA2aClient) stream(ctx context.Context, method string, params interface{}) (<-chan model2.SendStreamingMessageResponse, error) {
+	resp, err := c.post(ctx, method, params, "text/event-stream")
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		// The server answered with a single JSON-RPC response, usually an error
+		defer resp.Body.Close()
+		var jsonRpcResponse jsonrpc.JSONRPCResponse
+		if err := json.NewDecoder(resp.Body).Decode(&jsonRpcResponse); err != nil {
+			return nil, fmt.Errorf("error decoding JSON-RPC response: %v", err)
+		}
+		if jsonRpcResponse.Error != nil {
+			return nil, fmt.Errorf("JSON-RPC error: %w", jsonRpcResponse.Error)
+		}
+		return nil, fmt.Errorf("unexpected content type: %s", resp.Header.Get("Content-Type"))
+	}
+
+	responseChan := make(chan model2.SendStreamingMessageResponse)
+	go func() {
+		defer close(responseChan)
+		defer resp.Body.Close()
+
+		send := func(response model2.SendStreamingMessageResponse) bool {
+			select {
+			case responseChan <- response:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		fail := func(err error) {
+			send(&client.StreamError{Err: err})
+		}
+
+		reader := NewSSEReader(resp.Body)
+		for {
+			event, err := reader.Next()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					fail(fmt.Errorf("error reading event stream: %w", err))
+				}
+				return
+			}
+
+			var jsonRpcResponse jsonrpc.JSONRPCResponse
+			if err := json.Unmarshal([]byte(event.Data), &jsonRpcResponse); err != nil {
+				fail(fmt.Errorf("error decoding JSON-RPC response: %w", err))
+				return
+			}
+
+			if jsonRpcResponse.Error != nil {
+				fail(fmt.Errorf("JSON-RPC error: %w", jsonRpcResponse.Error))
+				return
+			}
+
+			if jsonRpcResponse.Result == nil {
+				continue
+			}
+
+			response, err := unmarshalStreamingResult(jsonRpcResponse.Result)
+			if err != nil {
+				fail(fmt.Errorf("error unmarshaling result: %w", err))
+				return
+			}
+			c.recordEventID(response, event.ID)
+			if !send(response) {
+				return
+			}
+		}
+	}()
+
+	return responseChan, nil
+}
+
+// SendMessage sends a task request to the server (non-streaming).
+// It fails when the agent answers with a direct message, see SendMessageResponse.
+func (c *DefaultA2aClient) SendMessage(ctx context.Context, params *model2.MessageSendParams) (*model2.Task, error) {
+	response, err := c.SendMessageResponse(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	task, ok := response.(*model2.Task)
+	if !ok {
+		return nil, fmt.Errorf("agent answered with a %T instead of a task", response)
+	}
+	return task, nil
+}
+
+// SendMessageResponse sends a message to the server (non-streaming) and returns the result of the agent:
+// a *model.Task, or the *model.Message it answered with directly.
+func (c *DefaultA2aClient) SendMessageResponse(ctx context.Context, params *model2.MessageSendParams) (model2.SendMessageResponse, error) {
+	var result json.RawMessage
+	if err := c.call(ctx, "message/send", params, &result); err != nil {
+		return nil, err
+	}
+	response, err := model2.UnmarshalSendMessageResponse(result)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling result: %v", err)
+	}
+	return response, nil
+}
+
+// SendMessageStream sends a task request and subscribes to streaming updates.
+func (c *DefaultA2aClient) SendMessageStream(ctx context.Context, params *model2.MessageSendParams) (<-chan model2.SendStreamingMessageResponse, error) {
+	return c.stream(ctx, "message/stream", params)
+}
+
+// GetTask retrieves the current state of a task.
+func (c *DefaultA2aClient) GetTask(ctx context.Context, params *model2.TaskQueryParams) (*model2.Task, error) {
+	var task model2.Task
+	if err := c.call(ctx, "tasks/get", params, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTaskStatusHistory retrieves the status transitions of a task, oldest first.
+// The agent only records them when it advertises the stateTransitionHistory capability.
+func (c *DefaultA2aClient) GetTaskStatusHistory(ctx context.Context, taskID string) ([]*model2.TaskStatusTransition, error) {
+	if !c.Supports("stateTransitionHistory") {
+		return nil, fmt.Errorf("agent does not support state transition history")
+	}
+	task, err := c.GetTask(ctx, model2.NewTaskQueryParams(taskID))
+	if err != nil {
+		return nil, err
+	}
+	return task.StatusHistory, nil
+}
+
+// CancelTask cancels a currently running task.
+func (c *DefaultA2aClient) CancelTask(ctx context.Context, params *model2.TaskIdParams) (*model2.Task, error) {
+	var task model2.Task
+	if err := c.call(ctx, "tasks/cancel", params, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SetTaskPushNotification sets or updates the push notification config for a task.
+func (c *DefaultA2aClient) SetTaskPushNotification(ctx context.Context, params *model2.TaskPushNotificationConfig) (*model2.TaskPushNotificationConfig, error) {
+	var config model2.TaskPushNotificationConfig
+	if err := c.call(ctx, "tasks/pushNotificationConfig/set", params, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetTaskPushNotification retrieves the currently configured push notification config for a task.
+func (c *DefaultA2aClient) GetTaskPushNotification(ctx context.Context, params *model2.TaskIdParams) (*model2.TaskPushNotificationConfig, error) {
+	var config model2.TaskPushNotificationConfig
+	if err := c.call(ctx, "tasks/pushNotificationConfig/get", params, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// ResubscribeTask resubscribes to updates for a task after a potential connection interruption.
+// Unless params.Offset is set, the stream resumes after the last event this client received for the task.
+func (c *DefaultA2aClient) ResubscribeTask(ctx context.Context, params *model2.TaskQueryParams) (<-chan model2.SendStreamingMessageResponse, error) {
+	if params != nil && params.Offset == nil {
+		if offset, ok := c.LastEventID(params.TaskID); ok {
+			resumed := *params
+			resumed.SetOffset(offset)
+			params = &resumed
+		}
+	}
+	return c.stream(ctx, "tasks/resubscribe", params)
+}
+
+// LastEventID returns the sequence id of the last streamed event received for the task
+func (c *DefaultA2aClient) LastEventID(taskID string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.lastEventIDs[taskID]
+	return id, ok
+}
+
+// recordEventID remembers the SSE event id of a streamed response for its task
+func (c *DefaultA2aClient) recordEventID(response model2.SendStreamingMessageResponse, eventID string) {
+	if eventID == "" {
+		return
+	}
+	id, err := strconv.ParseUint(eventID, 10, 64)
+	if err != nil {
+		return
+	}
+
+	var taskID string
+	switch r := response.(type) {
+	case *model2.Task:
+		taskID = r.ID
+	case *model2.Message:
+		taskID = r.TaskID
+	case *model2.TaskStatusUpdateEvent:
+		taskID = r.TaskID
+	case *model2.TaskArtifactUpdateEvent:
+		taskID = r.TaskID
+	}
+	if taskID == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id > c.lastEventIDs[taskID] {
+		c.lastEventIDs[taskID] = id
+	}
+}
+
+// resolveExtendedCardURL resolves the extended card URL against the endpoint host.
+func (c *DefaultA2aClient) resolveExtendedCardURL() (string, error) {
+	if c.extendedCardURL != "" {
+		return c.extendedCardURL, nil
+	}
+	endpoint, err := c.Endpoint()
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	return u.ResolveReference(&url.URL{Path: DefaultExtendedCardPath}).String(), nil
+}
+
+// RetrieveAuthenticatedExtendedAgentCard retrieves the authenticated extended AgentCard.
+func (c *DefaultA2aClient) RetrieveAuthenticatedExtendedAgentCard(ctx context.Context, authToken string) (*model2.AgentCard, error) {
+	cardURL, err := c.resolveExtendedCardURL()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", cardURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %v", err)
+	}
+
+	req.Header.Set("X-API-Key", authToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned non-200 status: %s", resp.Status)
+	}
+
+	var card model2.AgentCard
+	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
+		return nil, fmt.Errorf("error decoding agent card: %v", err)
+	}
+
+	return &card, nil
+}
+
+// Supports checks if the server likely supports optional methods based on agent card.
+func (c *DefaultA2aClient) Supports(capability string) bool {
+	if c.agentCard == nil {
+		c.agentCard = c.RetrieveAgentCard()
+	}
+
+	if c.agentCard == nil || c.agentCard.Capabilities == nil {
+		return false
+	}
+
+	// Check agent supports
+	switch strings.ToLower(capability) {
+	case "streaming":
+		return c.agentCard.Capabilities.Streaming
+	case "pushnotifications":
+		return c.agentCard.Capabilities.PushNotifications
+	case "statetransitionhistory":
+		return c.agentCard.Capabilities.StateTransitionHistory
+	default:
+		return false
+	}
+}
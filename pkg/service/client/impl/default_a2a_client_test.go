@@ -0,0 +1,796 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/jsonrpc"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/client"
+	clientimpl "github.com/a2ap/a2ago/pkg/service/client/impl"
+	"github.com/a2ap/a2ago/pkg/service/server"
+	serverimpl "github.com/a2ap/a2ago/pkg/service/server/impl"
+)
+
+// testAgentExecutor reports working, emits an artifact with text, file and data parts and completes.
+// It only needs the task, so it is served through server.NewTaskExecutorAdapter.
+type testAgentExecutor struct{}
+
+func (e *testAgentExecutor) Execute(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+	if err := queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Kind:      "status-update",
+		Status:    model.NewTaskStatus(model.TaskStateWorking),
+	}); err != nil {
+		return err
+	}
+
+	artifact := model.NewArtifact("result", "Result").WithParts([]model.Part{
+		model.NewTextPart("hello"),
+		model.NewFilePart(model.NewFileWithBytes([]byte("raw"), "raw.txt", "text/plain")),
+		model.NewFilePart(model.NewFileWithUriWithMetadata("doc.pdf", "application/pdf", "http://example.com/doc.pdf")),
+		model.NewDataPart(map[string]interface{}{"answer": 42.0}),
+	})
+	if err := queue.EnqueueEvent(&model.TaskArtifactUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Kind:      "artifact-update",
+		Artifact:  artifact,
+		LastChunk: true,
+	}); err != nil {
+		return err
+	}
+
+	return queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Kind:      "status-update",
+		Status:    model.NewTaskStatus(model.TaskStateCompleted),
+		Final:     true,
+	})
+}
+
+func (e *testAgentExecutor) Cancel(ctx context.Context, taskID string) error {
+	return nil
+}
+
+func (e *testAgentExecutor) GetTaskStatus(ctx context.Context, taskID string) (*model.TaskStatus, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *testAgentExecutor) GetTaskArtifact(ctx context.Context, taskID string, artifactID string) (*model.Artifact, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *testAgentExecutor) ListTaskArtifacts(ctx context.Context, taskID string) ([]*model.Artifact, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *testAgentExecutor) RegisterTaskNotification(ctx context.Context, config *model.TaskPushNotificationConfig) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (e *testAgentExecutor) GetTaskNotification(ctx context.Context, taskID string) (*model.TaskPushNotificationConfig, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// newTestServer serves a DefaultDispatcher behind httptest and returns a client whose card points at it
+func newTestServer(t *testing.T, executor server.AgentExecutor, opts ...serverimpl.ServerOption) (*httptest.Server, *clientimpl.DefaultA2aClient) {
+	t.Helper()
+
+	card := &model.AgentCard{
+		Name:         "Test Agent",
+		Version:      "test",
+		Capabilities: model.NewAgentCapabilities(true, false, true),
+	}
+	taskManager := serverimpl.NewInMemoryTaskManager(serverimpl.NewInMemoryTaskStore())
+	queueManager := serverimpl.NewInMemoryQueueManager()
+	a2aServer := serverimpl.NewDefaultA2AServer(taskManager, queueManager, executor, card, opts...)
+	dispatcher := serverimpl.NewDefaultDispatcher(a2aServer)
+
+	config := serverimpl.DefaultHttpHandlerConfig()
+	config.ExtendedCardAuthenticator = func(r *http.Request) error {
+		if r.Header.Get("X-API-Key") != "token" {
+			return errors.New("API key required")
+		}
+		return nil
+	}
+	ts := httptest.NewServer(serverimpl.NewHttpHandler(a2aServer, dispatcher, config))
+	t.Cleanup(ts.Close)
+	card.URL = ts.URL + serverimpl.DefaultJSONRPCPath
+
+	return ts, clientimpl.NewDefaultA2aClient(clientimpl.NewHttpCardResolver(ts.URL))
+}
+
+func newTextMessage(taskID, text string) *model.MessageSendParams {
+	message := model.NewUserMessage(taskID, "", []model.Part{model.NewTextPart(text)})
+	return model.NewMessageSendParams(message, nil)
+}
+
+func TestDefaultA2aClientAgainstDispatcher(t *testing.T) {
+	_, c := newTestServer(t, server.NewTaskExecutorAdapter(&testAgentExecutor{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	card := c.RetrieveAgentCard()
+	if card == nil || card.Name != "Test Agent" {
+		t.Fatalf("RetrieveAgentCard() = %+v, want the test card", card)
+	}
+	if !c.Supports("streaming") {
+		t.Errorf("Supports(streaming) = false, want true")
+	}
+
+	sent, err := c.SendMessage(ctx, newTextMessage("task-1", "hello"))
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if sent.ID != "task-1" || sent.Kind != model.KindTask || sent.Status.State != model.TaskStateCompleted || len(sent.Artifacts) != 1 {
+		t.Errorf("SendMessage() = %+v, want completed task-1 with its artifact", sent)
+	}
+	if len(sent.History) == 0 || sent.History[0].Role != model.RoleUser {
+		t.Errorf("SendMessage() history = %+v, want the user message first", sent.History)
+	}
+
+	task, err := c.GetTask(ctx, model.NewTaskQueryParams("task-1"))
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if task.ID != "task-1" || task.Status == nil || task.Status.State != model.TaskStateCompleted {
+		t.Errorf("GetTask() = %+v, want completed task-1", task)
+	}
+
+	if len(task.Artifacts) != 1 {
+		t.Fatalf("GetTask() artifacts = %d, want 1", len(task.Artifacts))
+	}
+	if artifact := task.Artifacts[0]; artifact.ArtifactID != "result" || artifact.Name != "Result" || len(artifact.Parts) != 4 {
+		t.Errorf("GetTask() artifact = %+v, want the result artifact with its four parts", artifact)
+	} else {
+		if tp, ok := artifact.Parts[0].(*model.TextPart); !ok || tp.Text != "hello" {
+			t.Errorf("GetTask() artifact first part = %#v, want text part", artifact.Parts[0])
+		}
+		if fp, ok := artifact.Parts[1].(*model.FilePart); !ok {
+			t.Errorf("GetTask() artifact second part = %#v, want file part", artifact.Parts[1])
+		} else if file, ok := fp.File.(*model.FileWithBytes); !ok || file.Name != "raw.txt" {
+			t.Errorf("GetTask() artifact second part file = %#v, want raw.txt with bytes", fp.File)
+		} else if content, err := file.GetBytes(); err != nil || string(content) != "raw" {
+			t.Errorf("GetTask() artifact second part bytes = %q, %v, want raw", content, err)
+		}
+		if fp, ok := artifact.Parts[2].(*model.FilePart); !ok {
+			t.Errorf("GetTask() artifact third part = %#v, want file part", artifact.Parts[2])
+		} else if file, ok := fp.File.(*model.FileWithUri); !ok || file.URI != "http://example.com/doc.pdf" || file.MimeType != "application/pdf" {
+			t.Errorf("GetTask() artifact third part file = %#v, want doc.pdf by URI", fp.File)
+		}
+		if dp, ok := artifact.Parts[3].(*model.DataPart); !ok {
+			t.Errorf("GetTask() artifact fourth part = %#v, want data part", artifact.Parts[3])
+		} else if data, ok := dp.Data.(map[string]interface{}); !ok || data["answer"] != 42.0 {
+			t.Errorf("GetTask() artifact fourth part data = %#v, want answer 42", dp.Data)
+		}
+	}
+
+	// A completed task can no longer be canceled
+	_, err = c.CancelTask(ctx, model.NewTaskIdParams(task.ID))
+	var cancelErr *jsonrpc.JSONRPCError
+	if !errors.As(err, &cancelErr) || cancelErr.Code != exception.TaskNotCancelable {
+		t.Errorf("CancelTask() error = %v, want TaskNotCancelable", err)
+	}
+	if task, err := c.GetTask(ctx, model.NewTaskQueryParams("task-1")); err != nil || task.Status.State != model.TaskStateCompleted {
+		t.Errorf("GetTask() after CancelTask() = %+v, %v, want completed task", task, err)
+	}
+
+	_, err = c.SetTaskPushNotification(ctx, model.NewTaskPushNotificationConfig(task.ID, "http://example.com/hook"))
+	if err == nil || !strings.Contains(err.Error(), "not implemented") {
+		t.Errorf("SetTaskPushNotification() error = %v, want JSON-RPC not implemented error", err)
+	}
+	_, err = c.GetTaskPushNotification(ctx, model.NewTaskIdParams(task.ID))
+	if err == nil || !strings.Contains(err.Error(), "not implemented") {
+		t.Errorf("GetTaskPushNotification() error = %v, want JSON-RPC not implemented error", err)
+	}
+
+	stream, err := c.SendMessageStream(ctx, newTextMessage("task-2", "stream"))
+	if err != nil {
+		t.Fatalf("SendMessageStream() error = %v", err)
+	}
+	var streamed []model.SendStreamingMessageResponse
+	for response := range stream {
+		if streamErr, ok := response.(*client.StreamError); ok {
+			t.Fatalf("SendMessageStream() stream error = %v", streamErr)
+		}
+		streamed = append(streamed, response)
+	}
+	if len(streamed) == 0 {
+		t.Fatalf("SendMessageStream() emitted nothing")
+	}
+	if first, ok := streamed[0].(*model.Task); !ok || first.Status.State != model.TaskStateSubmitted {
+		t.Errorf("SendMessageStream() first value = %#v, want the submitted *model.Task", streamed[0])
+	}
+	if last, ok := streamed[len(streamed)-1].(*model.TaskStatusUpdateEvent); !ok || !last.Final {
+		t.Errorf("SendMessageStream() last value = %#v, want a final *model.TaskStatusUpdateEvent", streamed[len(streamed)-1])
+	}
+
+	// The task has finished, so the server replies with the stored task
+	resubscribe, err := c.ResubscribeTask(ctx, model.NewTaskQueryParams(task.ID))
+	if err != nil {
+		t.Fatalf("ResubscribeTask() error = %v", err)
+	}
+	var resubscribed []model.SendStreamingMessageResponse
+	for response := range resubscribe {
+		resubscribed = append(resubscribed, response)
+	}
+	if len(resubscribed) != 1 {
+		t.Fatalf("ResubscribeTask() streamed %d values, want only the stored task", len(resubscribed))
+	}
+	if stored, ok := resubscribed[0].(*model.Task); !ok || stored.ID != task.ID || stored.Status.State != model.TaskStateCompleted {
+		t.Errorf("ResubscribeTask() value = %#v, want the completed task", resubscribed[0])
+	}
+
+	extended, err := c.RetrieveAuthenticatedExtendedAgentCard(ctx, "token")
+	if err != nil {
+		t.Fatalf("RetrieveAuthenticatedExtendedAgentCard() error = %v", err)
+	}
+	if extended.Name != card.Name {
+		t.Errorf("RetrieveAuthenticatedExtendedAgentCard() name = %q, want %q", extended.Name, card.Name)
+	}
+}
+
+func TestDefaultA2aClientEndpointOverride(t *testing.T) {
+	ts, _ := newTestServer(t, server.NewTaskExecutorAdapter(&testAgentExecutor{}))
+	card := &model.AgentCard{Name: "Stale", URL: "http://127.0.0.1:1/unreachable"}
+	c := clientimpl.NewDefaultA2aClientWithCard(card, nil,
+		clientimpl.WithEndpoint(ts.URL+serverimpl.DefaultJSONRPCPath))
+
+	endpoint, err := c.Endpoint()
+	if err != nil {
+		t.Fatalf("Endpoint() error = %v", err)
+	}
+	if endpoint != ts.URL+serverimpl.DefaultJSONRPCPath {
+		t.Errorf("Endpoint() = %q, want override", endpoint)
+	}
+	if _, err := c.SendMessage(context.Background(), newTextMessage("", "hello")); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+}
+
+// funcAgentExecutor runs the given function with the task of the request as Execute
+type funcAgentExecutor struct {
+	testAgentExecutor
+	execute func(ctx context.Context, task *model.Task, queue *server.EventQueue) error
+}
+
+func (e *funcAgentExecutor) Execute(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+	return e.execute(ctx, requestCtx.Task, queue)
+}
+
+func TestSendMessageStreamDeliversEventsWhileExecuting(t *testing.T) {
+	release := make(chan struct{})
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		if err := queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateWorking),
+		}); err != nil {
+			return err
+		}
+		// Block until the client has seen the working update
+		<-release
+		return queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateCompleted),
+			Final:     true,
+		})
+	}}
+	_, c := newTestServer(t, executor)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := c.SendMessageStream(ctx, newTextMessage("", "hello"))
+	if err != nil {
+		t.Fatalf("SendMessageStream() error = %v", err)
+	}
+
+	var states []model.TaskState
+	for response := range stream {
+		switch r := response.(type) {
+		case *client.StreamError:
+			t.Fatalf("SendMessageStream() stream error = %v", r)
+		case *model.Task:
+			if len(states) != 0 || r.Status.State != model.TaskStateSubmitted {
+				t.Errorf("SendMessageStream() streamed task in state %s after %v, want the submitted task first", r.Status.State, states)
+			}
+		case *model.TaskStatusUpdateEvent:
+			states = append(states, r.Status.State)
+			if r.Status.State == model.TaskStateWorking {
+				close(release)
+			}
+		}
+	}
+
+	if len(states) != 2 || states[0] != model.TaskStateWorking || states[1] != model.TaskStateCompleted {
+		t.Errorf("SendMessageStream() states = %v, want [working completed]", states)
+	}
+}
+
+func TestStreamOutlivesHTTPClientTimeout(t *testing.T) {
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		time.Sleep(300 * time.Millisecond)
+		return completed(task, queue, "done")
+	}}
+	ts, _ := newTestServer(t, executor)
+	c := clientimpl.NewDefaultA2aClient(clientimpl.NewHttpCardResolver(ts.URL),
+		clientimpl.WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := c.SendMessageStream(ctx, newTextMessage("", "hello"))
+	if err != nil {
+		t.Fatalf("SendMessageStream() error = %v", err)
+	}
+	var final model.TaskState
+	for response := range stream {
+		switch r := response.(type) {
+		case *client.StreamError:
+			t.Fatalf("SendMessageStream() stream error = %v", r)
+		case *model.TaskStatusUpdateEvent:
+			final = r.Status.State
+		}
+	}
+	if final != model.TaskStateCompleted {
+		t.Errorf("SendMessageStream() last state = %s, want completed after the client timeout", final)
+	}
+}
+
+func TestResubscribersReceiveEveryEvent(t *testing.T) {
+	release := make(chan struct{})
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		<-release
+		if err := queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateWorking),
+		}); err != nil {
+			return err
+		}
+		return queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateCompleted),
+			Final:     true,
+		})
+	}}
+	_, c := newTestServer(t, executor)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := c.SendMessageStream(ctx, newTextMessage("task-fanout", "hello"))
+	if err != nil {
+		t.Fatalf("SendMessageStream() error = %v", err)
+	}
+	// The stream is open once the task and its queue exist
+
+	subscribers := make([]<-chan model.SendStreamingMessageResponse, 2)
+	for i := range subscribers {
+		subscribers[i], err = c.ResubscribeTask(ctx, model.NewTaskQueryParams("task-fanout"))
+		if err != nil {
+			t.Fatalf("ResubscribeTask() error = %v", err)
+		}
+	}
+	close(release)
+
+	var streamStates []model.TaskState
+	for response := range stream {
+		if event, ok := response.(*model.TaskStatusUpdateEvent); ok {
+			streamStates = append(streamStates, event.Status.State)
+		}
+	}
+	if len(streamStates) != 2 {
+		t.Errorf("SendMessageStream() states = %v, want [working completed]", streamStates)
+	}
+
+	for i, subscriber := range subscribers {
+		var states []model.TaskState
+		for response := range subscriber {
+			switch r := response.(type) {
+			case *client.StreamError:
+				t.Fatalf("subscriber %d stream error = %v", i, r)
+			case *model.TaskStatusUpdateEvent:
+				states = append(states, r.Status.State)
+			}
+		}
+		if len(states) != 2 || states[0] != model.TaskStateWorking || states[1] != model.TaskStateCompleted {
+			t.Errorf("subscriber %d states = %v, want [working completed]", i, states)
+		}
+	}
+}
+
+func TestResubscribeReplaysMissedEvents(t *testing.T) {
+	release := make(chan struct{})
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		if err := queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateWorking),
+		}); err != nil {
+			return err
+		}
+		<-release
+		return queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateCompleted),
+			Final:     true,
+		})
+	}}
+	ts, c := newTestServer(t, executor)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := c.SendMessageStream(ctx, newTextMessage("task-replay", "hello"))
+	if err != nil {
+		t.Fatalf("SendMessageStream() error = %v", err)
+	}
+	for response := range stream {
+		if event, ok := response.(*model.TaskStatusUpdateEvent); ok && event.Status.State == model.TaskStateWorking {
+			break
+		}
+	}
+	lastEventID, ok := c.LastEventID("task-replay")
+	if !ok || lastEventID == 0 {
+		t.Fatalf("LastEventID() = %d, %v, want the id of the working event", lastEventID, ok)
+	}
+
+	// A new client replays every retained event, the original client resumes after what it has seen
+	other := clientimpl.NewDefaultA2aClient(clientimpl.NewHttpCardResolver(ts.URL))
+	replayed, err := other.ResubscribeTask(ctx, model.NewTaskQueryParamsWithOffset("task-replay", 0))
+	if err != nil {
+		t.Fatalf("ResubscribeTask(offset 0) error = %v", err)
+	}
+	resumed, err := c.ResubscribeTask(ctx, model.NewTaskQueryParams("task-replay"))
+	if err != nil {
+		t.Fatalf("ResubscribeTask() error = %v", err)
+	}
+	close(release)
+	go func() {
+		for range stream {
+		}
+	}()
+
+	collect := func(responses <-chan model.SendStreamingMessageResponse) []model.TaskState {
+		var states []model.TaskState
+		for response := range responses {
+			switch r := response.(type) {
+			case *client.StreamError:
+				t.Errorf("stream error = %v", r)
+			case *model.TaskStatusUpdateEvent:
+				states = append(states, r.Status.State)
+			}
+		}
+		return states
+	}
+	if states := collect(replayed); len(states) != 2 || states[0] != model.TaskStateWorking || states[1] != model.TaskStateCompleted {
+		t.Errorf("replayed states = %v, want [working completed]", states)
+	}
+	if states := collect(resumed); len(states) != 1 || states[0] != model.TaskStateCompleted {
+		t.Errorf("resumed states = %v, want [completed]", states)
+	}
+}
+
+func TestSendMessageNonBlockingReturnsSubmittedTask(t *testing.T) {
+	release := make(chan struct{})
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		<-release
+		return queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    model.NewTaskStatus(model.TaskStateCompleted),
+			Final:     true,
+		})
+	}}
+	_, c := newTestServer(t, executor)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	params := newTextMessage("task-background", "hello")
+	blocking := false
+	params.Configuration = &model.MessageSendConfiguration{Blocking: &blocking}
+	task, err := c.SendMessage(ctx, params)
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if task.ID != "task-background" || task.Status == nil || task.Status.State != model.TaskStateSubmitted {
+		t.Fatalf("SendMessage() = %+v, want submitted task-background", task)
+	}
+
+	close(release)
+	for {
+		task, err = c.GetTask(ctx, model.NewTaskQueryParams("task-background"))
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		if task.Status.State == model.TaskStateCompleted {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("task state = %s, want completed", task.Status.State)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestSendMessageToFinishedTaskIsRejected(t *testing.T) {
+	_, c := newTestServer(t, server.NewTaskExecutorAdapter(&testAgentExecutor{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.SendMessage(ctx, newTextMessage("task-final", "hello")); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	_, err := c.SendMessage(ctx, newTextMessage("task-final", "again"))
+	var rpcErr *jsonrpc.JSONRPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != exception.UnsupportedOperation {
+		t.Fatalf("SendMessage() to completed task error = %v, want UnsupportedOperation", err)
+	}
+
+	task, err := c.GetTask(ctx, model.NewTaskQueryParams("task-final"))
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if task.Status.State != model.TaskStateCompleted {
+		t.Errorf("GetTask() state = %s, want completed", task.Status.State)
+	}
+}
+
+func TestGetTaskStatusHistoryRecordsTransitions(t *testing.T) {
+	_, c := newTestServer(t, server.NewTaskExecutorAdapter(&testAgentExecutor{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := c.SendMessage(ctx, newTextMessage("task-history", "hello")); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	history, err := c.GetTaskStatusHistory(ctx, "task-history")
+	if err != nil {
+		t.Fatalf("GetTaskStatusHistory() error = %v", err)
+	}
+	want := []struct{ from, to model.TaskState }{
+		{"", model.TaskStateSubmitted},
+		{model.TaskStateSubmitted, model.TaskStateWorking},
+		{model.TaskStateWorking, model.TaskStateCompleted},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("GetTaskStatusHistory() returned %d transitions, want %d", len(history), len(want))
+	}
+	for i, transition := range history {
+		if transition.From != want[i].from || transition.State != want[i].to {
+			t.Errorf("transition[%d] = %s -> %s, want %s -> %s", i, transition.From, transition.State, want[i].from, want[i].to)
+		}
+		if transition.Timestamp == "" {
+			t.Errorf("transition[%d] has no timestamp", i)
+		}
+	}
+}
+
+// completed completes the task with an agent message echoing text
+func completed(task *model.Task, queue *server.EventQueue, text string) error {
+	status := model.NewTaskStatus(model.TaskStateCompleted)
+	status.Message = model.NewAgentMessage(task.ID, task.ContextID, []model.Part{model.NewTextPart(text)})
+	return queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Status:    status,
+		Final:     true,
+	})
+}
+
+// requestContextExecutor records the request context it is executed with and completes the task
+type requestContextExecutor struct {
+	testAgentExecutor
+	received chan *model.RequestContext
+}
+
+func (e *requestContextExecutor) Execute(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+	e.received <- requestCtx
+	return completed(requestCtx.Task, queue, "done")
+}
+
+func TestExecutorReceivesRequestContext(t *testing.T) {
+	executor := &requestContextExecutor{received: make(chan *model.RequestContext, 2)}
+	card := &model.AgentCard{Name: "Test Agent", Capabilities: model.NewAgentCapabilities(true, false, true)}
+	a2aServer := serverimpl.NewDefaultA2AServer(
+		serverimpl.NewInMemoryTaskManager(serverimpl.NewInMemoryTaskStore()),
+		serverimpl.NewInMemoryQueueManager(), executor, card)
+	ts := httptest.NewServer(serverimpl.NewHttpHandler(a2aServer, serverimpl.NewDefaultDispatcher(a2aServer), &serverimpl.HttpHandlerConfig{
+		Authenticator: func(r *http.Request) (*model.User, error) {
+			if r.Header.Get("Authorization") != "Bearer alice" {
+				return nil, fmt.Errorf("missing credentials")
+			}
+			return model.NewUser("alice", nil, nil), nil
+		},
+	}))
+	defer ts.Close()
+	card.URL = ts.URL + serverimpl.DefaultJSONRPCPath
+
+	httpClient := &http.Client{Transport: headerTransport{"Authorization": "Bearer alice"}}
+	c := clientimpl.NewDefaultA2aClient(clientimpl.NewHttpCardResolver(ts.URL),
+		clientimpl.WithHTTPClient(httpClient), clientimpl.WithExtensions("urn:ext:a", "urn:ext:b"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A related task shares the context of the message
+	first, err := c.SendMessage(ctx, newTextMessage("task-related", "first"))
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	<-executor.received
+
+	params := newTextMessage("task-request-context", "hello")
+	params.Message.ContextID = first.ContextID
+	params.Message.WithReferenceTaskIDs(first.ID).WithExtensions("urn:ext:a")
+	params.Configuration = &model.MessageSendConfiguration{AcceptedOutputModes: []string{"text/plain"}}
+	if _, err := c.SendMessage(ctx, params); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	requestCtx := <-executor.received
+
+	if requestCtx.Message == nil || requestCtx.Message.Parts[0].(*model.TextPart).Text != "hello" {
+		t.Errorf("Message = %+v, want the incoming message", requestCtx.Message)
+	}
+	if message := requestCtx.Message; message.MessageID != params.Message.MessageID || message.Role != model.RoleUser ||
+		len(message.Extensions) != 1 || message.Extensions[0] != "urn:ext:a" {
+		t.Errorf("Message = %+v, want the message ID, role and extensions sent by the client", message)
+	}
+	if len(requestCtx.ReferenceTasks) != 1 || requestCtx.ReferenceTasks[0].ID != "task-related" {
+		t.Errorf("ReferenceTasks = %v, want [task-related]", requestCtx.ReferenceTasks)
+	}
+	if modes := requestCtx.GetAcceptedOutputModes(); len(modes) != 1 || modes[0] != "text/plain" {
+		t.Errorf("GetAcceptedOutputModes() = %v, want [text/plain]", modes)
+	}
+	if len(requestCtx.RelatedTasks) != 1 || requestCtx.RelatedTasks[0].ID != "task-related" {
+		t.Errorf("RelatedTasks = %v, want [task-related]", requestCtx.RelatedTasks)
+	}
+	if got := strings.Join(requestCtx.Extensions, ","); got != "urn:ext:a,urn:ext:b" {
+		t.Errorf("Extensions = %s, want urn:ext:a,urn:ext:b", got)
+	}
+	if requestCtx.User == nil || requestCtx.User.Name != "alice" {
+		t.Errorf("User = %+v, want alice", requestCtx.User)
+	}
+
+	// Requests the authenticator rejects never reach the executor
+	anonymous := clientimpl.NewDefaultA2aClientWithCard(card, nil)
+	if _, err := anonymous.SendMessage(ctx, newTextMessage("", "hello")); err == nil {
+		t.Errorf("SendMessage() without credentials succeeded, want an error")
+	}
+}
+
+// headerTransport sets fixed headers on every request
+type headerTransport map[string]string
+
+func (t headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	for name, value := range t {
+		r.Header.Set(name, value)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestSendMessageResponseDecodesDirectReply(t *testing.T) {
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		return server.NewTaskUpdater(queue, task.ID, task.ContextID).Reply([]model.Part{model.NewTextPart("pong")})
+	}}
+	_, c := newTestServer(t, executor)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := c.SendMessageResponse(ctx, newTextMessage("task-direct", "ping"))
+	if err != nil {
+		t.Fatalf("SendMessageResponse() error = %v", err)
+	}
+	if reply, ok := response.(*model.Message); !ok || reply.Role != model.RoleAgent || reply.Parts[0].(*model.TextPart).Text != "pong" {
+		t.Fatalf("SendMessageResponse() = %#v, want the agent message", response)
+	}
+	if _, err := c.SendMessage(ctx, newTextMessage("task-direct-2", "ping")); err == nil {
+		t.Errorf("SendMessage() error = nil, want an error for a direct message")
+	}
+}
+
+func TestMalformedMessagesAreInvalidParams(t *testing.T) {
+	_, c := newTestServer(t, server.NewTaskExecutorAdapter(&testAgentExecutor{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		mutate func(message *model.Message)
+	}{
+		{"missing message id", func(message *model.Message) { message.MessageID = "" }},
+		{"agent role", func(message *model.Message) { message.Role = model.RoleAgent }},
+		{"unknown reference task", func(message *model.Message) { message.WithReferenceTaskIDs("task-unknown") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := newTextMessage("task-malformed", "hello")
+			tt.mutate(params.Message)
+
+			var rpcErr *jsonrpc.JSONRPCError
+			if _, err := c.SendMessage(ctx, params); !errors.As(err, &rpcErr) || rpcErr.Code != exception.InvalidParams {
+				t.Errorf("SendMessage() error = %v, want InvalidParams", err)
+			}
+			stream, err := c.SendMessageStream(ctx, params)
+			if err != nil {
+				t.Fatalf("SendMessageStream() error = %v", err)
+			}
+			for response := range stream {
+				streamErr, ok := response.(*client.StreamError)
+				if !ok || !errors.As(streamErr, &rpcErr) || rpcErr.Code != exception.InvalidParams {
+					t.Errorf("SendMessageStream() streamed %#v, want a StreamError with InvalidParams", response)
+				}
+			}
+			if task, err := c.GetTask(ctx, model.NewTaskQueryParams("task-malformed")); err == nil && task != nil && task.ID != "" {
+				t.Errorf("GetTask() = %+v, want the malformed message not to create a task", task)
+			}
+		})
+	}
+}
+
+func TestHistoryLengthLimitsReturnedHistory(t *testing.T) {
+	executor := &funcAgentExecutor{execute: func(ctx context.Context, task *model.Task, queue *server.EventQueue) error {
+		updater := server.NewTaskUpdater(queue, task.ID, task.ContextID)
+		return updater.Complete(updater.NewAgentMessage([]model.Part{model.NewTextPart("done")}))
+	}}
+	_, c := newTestServer(t, executor)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	params := newTextMessage("task-history", "hello")
+	historyLength := 1
+	params.Configuration = &model.MessageSendConfiguration{HistoryLength: &historyLength}
+	sent, err := c.SendMessage(ctx, params)
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(sent.History) != 1 || sent.History[0].Role != model.RoleAgent {
+		t.Errorf("SendMessage() history = %+v, want only the agent message", sent.History)
+	}
+
+	for _, tt := range []struct {
+		params *model.TaskQueryParams
+		want   int
+	}{
+		{model.NewTaskQueryParamsWithHistory("task-history", 0), 0},
+		{model.NewTaskQueryParamsWithHistory("task-history", 1), 1},
+		{model.NewTaskQueryParamsWithHistory("task-history", 5), 2},
+		{model.NewTaskQueryParams("task-history"), 2},
+	} {
+		task, err := c.GetTask(ctx, tt.params)
+		if err != nil {
+			t.Fatalf("GetTask(%v) error = %v", tt.params.HistoryLength, err)
+		}
+		if len(task.History) != tt.want {
+			t.Errorf("GetTask(%v) history = %d messages, want %d", tt.params.HistoryLength, len(task.History), tt.want)
+		}
+		if tt.want > 0 && task.History[len(task.History)-1].Role != model.RoleAgent {
+			t.Errorf("GetTask(%v) last message = %+v, want the agent message", tt.params.HistoryLength, task.History[len(task.History)-1])
+		}
+	}
+
+	var rpcErr *jsonrpc.JSONRPCError
+	if _, err := c.GetTask(ctx, model.NewTaskQueryParamsWithHistory("task-history", -1)); !errors.As(err, &rpcErr) || rpcErr.Code != exception.InvalidParams {
+		t.Errorf("GetTask(-1) error = %v, want InvalidParams", err)
+	}
+	negative := -1
+	params = newTextMessage("task-history-negative", "hello")
+	params.Configuration = &model.MessageSendConfiguration{HistoryLength: &negative}
+	if _, err := c.SendMessage(ctx, params); !errors.As(err, &rpcErr) || rpcErr.Code != exception.InvalidParams {
+		t.Errorf("SendMessage(historyLength -1) error = %v, want InvalidParams", err)
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent represents a single Server-Sent Events frame
+type SSEEvent struct {
+	// ID is the last event ID seen on the stream when this event was dispatched
+	ID string
+	// Event is the event type, "message" when the frame does not set one
+	Event string
+	// Data is the event payload, multiple data lines are joined with "\n"
+	Data string
+	// Retry is the reconnection time requested by the server, zero when unset
+	Retry time.Duration
+}
+
+// SSEReader reads Server-Sent Events frames from a stream following the
+// text/event-stream format: data, event, id and retry fields, comment lines
+// used as heartbeats, and blank lines dispatching events.
+type SSEReader struct {
+	reader      *bufio.Reader
+	lastEventID string
+	retry       time.Duration
+}
+
+// NewSSEReader creates a new SSEReader reading from r
+func NewSSEReader(r io.Reader) *SSEReader {
+	return &SSEReader{
+		reader: bufio.NewReader(r),
+	}
+}
+
+// LastEventID returns the last event ID received on the stream
+func (r *SSEReader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Next reads the next event from the stream.
+// It returns io.EOF when the stream ends; an event not terminated by a blank line is discarded.
+func (r *SSEReader) Next() (*SSEEvent, error) {
+	var (
+		eventType string
+		data      strings.Builder
+		hasData   bool
+	)
+
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if !hasData {
+				// Nothing to dispatch, reset the event type and keep reading
+				eventType = ""
+				if err == io.EOF {
+					return nil, io.EOF
+				}
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return &SSEEvent{
+				ID:    r.lastEventID,
+				Event: eventType,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+				Retry: r.retry,
+			}, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			// Comment line, typically a heartbeat
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "event":
+			eventType = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastEventID = value
+			}
+		case "retry":
+			if ms, convErr := strconv.Atoi(value); convErr == nil && ms >= 0 {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package client_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	clientimpl "github.com/a2ap/a2ago/pkg/service/client/impl"
+)
+
+func TestSSEReaderNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		stream     string
+		want       []clientimpl.SSEEvent
+		wantLastID string
+	}{
+		{
+			name:   "single data line",
+			stream: "data: hello\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: "hello"}},
+		},
+		{
+			name:   "multi-line data is joined with newlines",
+			stream: "data: first\ndata: second\ndata:third\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: "first\nsecond\nthird"}},
+		},
+		{
+			name:   "empty data line dispatches an empty event",
+			stream: "data\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: ""}},
+		},
+		{
+			name:   "event type applies to one event",
+			stream: "event: update\ndata: a\n\ndata: b\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "update", Data: "a"}, {Event: "message", Data: "b"}},
+		},
+		{
+			name:       "id is kept for the following events",
+			stream:     "id: 1\ndata: a\n\ndata: b\n\nid: 2\ndata: c\n\n",
+			want:       []clientimpl.SSEEvent{{ID: "1", Event: "message", Data: "a"}, {ID: "1", Event: "message", Data: "b"}, {ID: "2", Event: "message", Data: "c"}},
+			wantLastID: "2",
+		},
+		{
+			name:       "id containing NUL is ignored",
+			stream:     "id: 1\ndata: a\n\nid: 2\x003\ndata: b\n\n",
+			want:       []clientimpl.SSEEvent{{ID: "1", Event: "message", Data: "a"}, {ID: "1", Event: "message", Data: "b"}},
+			wantLastID: "1",
+		},
+		{
+			name:   "retry is kept and invalid values are ignored",
+			stream: "retry: 1500\ndata: a\n\nretry: soon\ndata: b\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: "a", Retry: 1500 * time.Millisecond}, {Event: "message", Data: "b", Retry: 1500 * time.Millisecond}},
+		},
+		{
+			name:   "comments and unknown fields are skipped",
+			stream: ": heartbeat\nfoo: bar\ndata: a\n: another\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: "a"}},
+		},
+		{
+			name:   "blank lines without data dispatch nothing",
+			stream: "\n\nevent: ignored\n\ndata: a\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: "a"}},
+		},
+		{
+			name:       "CRLF line endings",
+			stream:     "id: 7\r\nevent: update\r\ndata: first\r\ndata: second\r\n\r\n",
+			want:       []clientimpl.SSEEvent{{ID: "7", Event: "update", Data: "first\nsecond"}},
+			wantLastID: "7",
+		},
+		{
+			name:   "only the first space after the colon is removed",
+			stream: "data:  indented\n\n",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: " indented"}},
+		},
+		{
+			name:   "unterminated event is discarded",
+			stream: "data: a\n\ndata: b",
+			want:   []clientimpl.SSEEvent{{Event: "message", Data: "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := clientimpl.NewSSEReader(strings.NewReader(tt.stream))
+
+			var got []clientimpl.SSEEvent
+			for {
+				event, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				got = append(got, *event)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() events = %+v, want %+v", got, tt.want)
+			}
+			if reader.LastEventID() != tt.wantLastID {
+				t.Errorf("LastEventID() = %q, want %q", reader.LastEventID(), tt.wantLastID)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"fmt"
+)
+
+// StreamError is emitted on a streaming channel when the stream fails,
+// either on the transport or with a JSON-RPC error returned by the server.
+// It is always the last value sent before the channel is closed.
+type StreamError struct {
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the error message
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("stream error: %v", e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// IsSendStreamingMessageResponse implements the SendStreamingMessageResponse interface
+func (e *StreamError) IsSendStreamingMessageResponse() {}
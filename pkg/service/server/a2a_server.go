@@ -3,20 +3,26 @@ package server
 import (
 	"context"
 
-	"github.com/a2ap/a2ago/internal/model"
+	"github.com/a2ap/a2ago/pkg/model"
 )
 
 // A2AServer defines the interface for an A2A server
 type A2AServer interface {
-	// HandleMessage handles a message request
+	// HandleMessage handles a message request.
+	// A blocking request whose execution deadline passes gets the task failed with the timeout reason.
 	HandleMessage(ctx context.Context, params *model.MessageSendParams) (*model.SendMessageResponse, error)
 
-	// HandleMessageStream handles a streaming message request
+	// HandleMessageStream handles a streaming message request.
+	// Responses produced from queue events are *SequencedResponse values carrying the event sequence id.
 	HandleMessageStream(ctx context.Context, params *model.MessageSendParams) (<-chan *model.SendStreamingMessageResponse, error)
 
 	// GetTask gets a task by ID
 	GetTask(ctx context.Context, taskID string) (*model.Task, error)
 
+	// QueryTask gets a task for tasks/get, with its history limited to the requested historyLength.
+	// A negative historyLength is rejected with exception.InvalidParams.
+	QueryTask(ctx context.Context, params *model.TaskQueryParams) (*model.Task, error)
+
 	// CancelTask cancels a task
 	CancelTask(ctx context.Context, taskID string) (*model.Task, error)
 
@@ -26,8 +32,10 @@ type A2AServer interface {
 	// GetTaskPushNotification gets the push notification configuration for a task
 	GetTaskPushNotification(ctx context.Context, taskID string) (*model.TaskPushNotificationConfig, error)
 
-	// SubscribeToTaskUpdates subscribes to task updates
-	SubscribeToTaskUpdates(ctx context.Context, taskID string) (<-chan *model.SendStreamingMessageResponse, error)
+	// SubscribeToTaskUpdates subscribes to task updates.
+	// When params.Offset is set, the retained events after that sequence id are replayed before live delivery.
+	// A task no run is streaming any more is answered with the stored task alone.
+	SubscribeToTaskUpdates(ctx context.Context, params *model.TaskQueryParams) (<-chan *model.SendStreamingMessageResponse, error)
 
 	// GetSelfAgentCard retrieves the AgentCard for this server
 	GetSelfAgentCard() *model.AgentCard
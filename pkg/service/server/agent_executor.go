@@ -3,11 +3,42 @@ package server
 import (
 	"context"
 
-	"github.com/a2ap/a2ago/internal/model"
+	"github.com/a2ap/a2ago/pkg/model"
 )
 
 // AgentExecutor defines the interface for executing tasks on agents.
 type AgentExecutor interface {
+	// Execute executes the task of a request on an agent.
+	// The request context carries the task, the incoming message and its configuration,
+	// the related tasks, the requested extensions and the authenticated user.
+	// A follow-up message to a task waiting for input either re-invokes Execute with the full conversation
+	// in the task history or, while Execute is still running, is delivered through AwaitInput.
+	// An agent that needs no task can answer a new request with a single *model.Message as its first event,
+	// see TaskUpdater.Reply.
+	Execute(ctx context.Context, requestCtx *model.RequestContext, queue *EventQueue) error
+
+	// Cancel cancels a task.
+	Cancel(ctx context.Context, taskID string) error
+
+	// GetTaskStatus gets the status of a task.
+	GetTaskStatus(ctx context.Context, taskID string) (*model.TaskStatus, error)
+
+	// GetTaskArtifact gets an artifact from a task.
+	GetTaskArtifact(ctx context.Context, taskID string, artifactID string) (*model.Artifact, error)
+
+	// ListTaskArtifacts lists all artifacts for a task.
+	ListTaskArtifacts(ctx context.Context, taskID string) ([]*model.Artifact, error)
+
+	// RegisterTaskNotification registers a task notification.
+	RegisterTaskNotification(ctx context.Context, config *model.TaskPushNotificationConfig) error
+
+	// GetTaskNotification gets a task notification.
+	GetTaskNotification(ctx context.Context, taskID string) (*model.TaskPushNotificationConfig, error)
+}
+
+// TaskExecutor is an agent executor that only needs the task of a request,
+// it is adapted to an AgentExecutor with NewTaskExecutorAdapter
+type TaskExecutor interface {
 	// Execute executes a task on an agent.
 	Execute(ctx context.Context, task *model.Task, queue *EventQueue) error
 
@@ -29,3 +60,18 @@ type AgentExecutor interface {
 	// GetTaskNotification gets a task notification.
 	GetTaskNotification(ctx context.Context, taskID string) (*model.TaskPushNotificationConfig, error)
 }
+
+// TaskExecutorAdapter adapts a TaskExecutor to the AgentExecutor interface
+type TaskExecutorAdapter struct {
+	TaskExecutor
+}
+
+// NewTaskExecutorAdapter creates a new TaskExecutorAdapter
+func NewTaskExecutorAdapter(executor TaskExecutor) AgentExecutor {
+	return &TaskExecutorAdapter{TaskExecutor: executor}
+}
+
+// Execute executes the task of the request with the adapted TaskExecutor
+func (a *TaskExecutorAdapter) Execute(ctx context.Context, requestCtx *model.RequestContext, queue *EventQueue) error {
+	return a.TaskExecutor.Execute(ctx, requestCtx.Task, queue)
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// userKey is the context key of the authenticated caller
+type userKey struct{}
+
+// extensionsKey is the context key of the protocol extensions requested by the caller
+type extensionsKey struct{}
+
+// deadlineKey is the context key of the deadline supplied by the caller
+type deadlineKey struct{}
+
+// WithUser returns a context carrying the authenticated caller of the request
+func WithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the authenticated caller of the request, nil for an anonymous request
+func UserFromContext(ctx context.Context) *model.User {
+	user, _ := ctx.Value(userKey{}).(*model.User)
+	return user
+}
+
+// WithExtensions returns a context carrying the URIs of the protocol extensions requested by the caller
+func WithExtensions(ctx context.Context, extensions []string) context.Context {
+	return context.WithValue(ctx, extensionsKey{}, extensions)
+}
+
+// ExtensionsFromContext returns the URIs of the protocol extensions requested by the caller
+func ExtensionsFromContext(ctx context.Context) []string {
+	extensions, _ := ctx.Value(extensionsKey{}).([]string)
+	return extensions
+}
+
+// WithClientDeadline returns a context carrying the time by which the caller needs the task finished.
+// Unlike a context deadline it also bounds runs that outlive the request, such as non-blocking sends.
+func WithClientDeadline(ctx context.Context, deadline time.Time) context.Context {
+	return context.WithValue(ctx, deadlineKey{}, deadline)
+}
+
+// ClientDeadlineFromContext returns the deadline supplied by the caller
+func ClientDeadlineFromContext(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(deadlineKey{}).(time.Time)
+	return deadline, ok
+}
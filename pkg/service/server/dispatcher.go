@@ -1,16 +1,18 @@
 package server
 
 import (
-	"github.com/a2ap/a2ago/internal/jsonrpc"
+	"context"
+
+	"github.com/a2ap/a2ago/pkg/jsonrpc"
 )
 
 // Dispatcher defines the interface for handling JSON-RPC requests
 type Dispatcher interface {
-	// Dispatch handles synchronous JSON-RPC requests
-	Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.JSONRPCResponse
+	// Dispatch handles synchronous JSON-RPC requests, ctx is canceled when the client goes away
+	Dispatch(ctx context.Context, request *jsonrpc.JSONRPCRequest) *jsonrpc.JSONRPCResponse
 
-	// DispatchStream handles streaming JSON-RPC requests
-	DispatchStream(request *jsonrpc.JSONRPCRequest) (<-chan *jsonrpc.JSONRPCResponse, error)
+	// DispatchStream handles streaming JSON-RPC requests, ctx is canceled when the client goes away
+	DispatchStream(ctx context.Context, request *jsonrpc.JSONRPCRequest) (<-chan *jsonrpc.JSONRPCResponse, error)
 }
 
 // DefaultDispatcher implements the Dispatcher interface for handling JSON-RPC requests
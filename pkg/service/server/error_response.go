@@ -0,0 +1,18 @@
+package server
+
+// ErrorResponse ends a stream with an error.
+// Transports report Err as a protocol error, for example a JSON-RPC error, instead of a result.
+type ErrorResponse struct {
+	// Err is the error reported to the client
+	Err error
+}
+
+// NewErrorResponse creates a new ErrorResponse
+func NewErrorResponse(err error) *ErrorResponse {
+	return &ErrorResponse{
+		Err: err,
+	}
+}
+
+// IsSendStreamingMessageResponse implements the SendStreamingMessageResponse interface
+func (r *ErrorResponse) IsSendStreamingMessageResponse() {}
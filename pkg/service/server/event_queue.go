@@ -1,91 +1,484 @@
 package server
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"sync"
 )
 
-// EventQueue represents a queue for managing events
-// 支持热流：EnqueueEvent 实时推送到 channel，AsFlux 返回 channel
-// 关闭时关闭 channel
-// 兼容历史事件回放
+const (
+	// DefaultQueueCapacity is the default number of undelivered events a queue buffers
+	DefaultQueueCapacity = 32
 
+	// DefaultQueueRetention is the default number of delivered events a queue keeps for replay
+	DefaultQueueRetention = 1024
+)
+
+var (
+	// ErrQueueClosed is returned when enqueuing to a closed queue
+	ErrQueueClosed = errors.New("queue is closed")
+
+	// ErrQueueFull is returned by the OverflowFail policy when the queue is full
+	ErrQueueFull = errors.New("queue is full")
+)
+
+// OverflowPolicy decides what EnqueueEvent does when the queue already buffers Capacity undelivered events
+type OverflowPolicy int
+
+const (
+	// OverflowGrow keeps buffering beyond the capacity, no event is ever dropped.
+	// The buffer is unbounded: a consumer that stops reading makes the queue grow for as long as the
+	// producer enqueues, so use another policy when consumers cannot be trusted to keep up.
+	OverflowGrow OverflowPolicy = iota
+
+	// OverflowBlock blocks the producer until an event is delivered, the context is done or the queue is closed.
+	// Only the producer of a queue blocks: a tap blocking would stall the producer and every other tap,
+	// so a full tap with this policy is closed instead, see Tap.
+	OverflowBlock
+
+	// OverflowDropOldest discards the oldest undelivered event to make room for the new one
+	OverflowDropOldest
+
+	// OverflowDropNewest discards the new event, which gets no sequence id and does not reach the taps
+	OverflowDropNewest
+
+	// OverflowFail rejects the new event with ErrQueueFull
+	OverflowFail
+)
+
+// String returns the name of the overflow policy
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowGrow:
+		return "grow"
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowDropNewest:
+		return "drop-newest"
+	case OverflowFail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// QueueOptions configures an EventQueue
+type QueueOptions struct {
+	// Capacity is the number of undelivered events buffered before the overflow policy applies
+	Capacity int
+	// OverflowPolicy decides what happens when Capacity is reached
+	OverflowPolicy OverflowPolicy
+	// Retention is the number of most recent events kept for replay, zero keeps none and a negative value keeps all
+	Retention int
+	// Validator checks every event enqueued to the queue, an error rejects the event and is returned to the producer.
+	// It is called in enqueue order and is not inherited by child queues.
+	Validator func(event interface{}) error
+}
+
+// QueueOption configures QueueOptions
+type QueueOption func(*QueueOptions)
+
+// WithCapacity sets the number of undelivered events buffered before the overflow policy applies
+func WithCapacity(capacity int) QueueOption {
+	return func(o *QueueOptions) {
+		o.Capacity = capacity
+	}
+}
+
+// WithOverflowPolicy sets the overflow policy of the queue
+func WithOverflowPolicy(policy OverflowPolicy) QueueOption {
+	return func(o *QueueOptions) {
+		o.OverflowPolicy = policy
+	}
+}
+
+// WithRetention sets the number of most recent events kept for replay,
+// zero keeps none and a negative value keeps all
+func WithRetention(retention int) QueueOption {
+	return func(o *QueueOptions) {
+		o.Retention = retention
+	}
+}
+
+// WithValidator sets the function checking every event enqueued to the queue
+func WithValidator(validator func(event interface{}) error) QueueOption {
+	return func(o *QueueOptions) {
+		o.Validator = validator
+	}
+}
+
+// NewQueueOptions returns the default queue options with opts applied
+func NewQueueOptions(opts ...QueueOption) QueueOptions {
+	options := QueueOptions{
+		Capacity:       DefaultQueueCapacity,
+		OverflowPolicy: OverflowGrow,
+		Retention:      DefaultQueueRetention,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Capacity <= 0 {
+		options.Capacity = DefaultQueueCapacity
+	}
+	return options
+}
+
+// QueueStats are counters describing the traffic of an EventQueue
+type QueueStats struct {
+	// Enqueued is the number of events accepted by the queue
+	Enqueued uint64
+	// Dropped is the number of events discarded by the overflow policy
+	Dropped uint64
+	// Rejected is the number of events refused with ErrQueueFull, a done context or by the validator
+	Rejected uint64
+	// Pending is the number of events not yet delivered to the consumer
+	Pending int
+}
+
+// QueueEvent is an event together with its sequence id.
+// Sequence ids start at 1 and increase monotonically per task queue; child queues keep the ids of their parent.
+type QueueEvent struct {
+	// Seq is the sequence id of the event
+	Seq uint64
+	// Event is the enqueued event
+	Event interface{}
+}
+
+// EventQueue represents a queue for managing events.
+// Events are delivered in order on the channel returned by AsFlux; the queue buffers
+// up to Capacity undelivered events and applies its OverflowPolicy beyond that.
+// Closing the queue closes the channel once every buffered event has been delivered,
+// so a consumer that calls AsFlux must keep reading until the channel is closed.
 type EventQueue struct {
-	events   []interface{}
+	events   []QueueEvent // most recent events kept for replay
+	lastSeq  uint64
+	sendMu   sync.Mutex // held from assigning a sequence id until the children got the event, taken before mu
 	mu       sync.RWMutex
 	closed   bool
 	children []*EventQueue
+	parent   *EventQueue
 
-	eventsCh chan interface{} // 新增：事件热流 channel
-	once     sync.Once        // 保证只关闭一次
+	options QueueOptions
+	pending []QueueEvent // events not yet delivered on sequencedCh
+	stats   QueueStats
+
+	sequencedCh chan QueueEvent  // delivers pending events in order
+	eventsCh    chan interface{} // delivers the events of sequencedCh without their sequence ids
+	wake        chan struct{}    // wakes the delivery goroutine
+	space       chan struct{}    // closed and replaced whenever an event is delivered
+	done        chan struct{}    // closed when the queue is closed
+	once        sync.Once        // starts the delivery goroutine once
+	fluxOnce    sync.Once        // starts the AsFlux goroutine once
 }
 
 // NewEventQueue creates a new EventQueue
-func NewEventQueue() *EventQueue {
+func NewEventQueue(opts ...QueueOption) *EventQueue {
 	return &EventQueue{
-		events:   make([]interface{}, 0),
-		children: make([]*EventQueue, 0),
-		eventsCh: make(chan interface{}, 32), // 带缓冲，防止阻塞
+		events:      make([]QueueEvent, 0),
+		children:    make([]*EventQueue, 0),
+		options:     NewQueueOptions(opts...),
+		pending:     make([]QueueEvent, 0),
+		sequencedCh: make(chan QueueEvent),
+		eventsCh:    make(chan interface{}),
+		wake:        make(chan struct{}, 1),
+		space:       make(chan struct{}),
+		done:        make(chan struct{}),
 	}
 }
 
-// EnqueueEvent enqueues an event to this queue and all its children
+// Options returns the options of the queue
+func (q *EventQueue) Options() QueueOptions {
+	return q.options
+}
+
+// EnqueueEvent enqueues an event to this queue and all its children.
+// With the OverflowBlock policy it waits until the event fits or the queue is closed.
 func (q *EventQueue) EnqueueEvent(event interface{}) error {
+	return q.EnqueueEventContext(context.Background(), event)
+}
+
+// EnqueueEventContext enqueues an event to this queue and all its children.
+// With the OverflowBlock policy it waits until the event fits, ctx is done or the queue is closed.
+func (q *EventQueue) EnqueueEventContext(ctx context.Context, event interface{}) error {
+	return q.enqueue(ctx, QueueEvent{Event: event}, false)
+}
+
+// enqueue enqueues an event, assigning the next sequence id unless it already has one.
+// An event propagated from the parent never blocks: it is rejected with ErrQueueFull instead.
+func (q *EventQueue) enqueue(ctx context.Context, event QueueEvent, propagated bool) error {
+	// Concurrent producers hand their events to the children in sequence order
+	q.sendMu.Lock()
+	defer q.sendMu.Unlock()
 	q.mu.Lock()
-	defer q.mu.Unlock()
+	accepted := true
+	for accepted && !q.closed && q.options.OverflowPolicy != OverflowGrow && len(q.pending) >= q.options.Capacity {
+		switch q.options.OverflowPolicy {
+		case OverflowDropOldest:
+			q.pending[0] = QueueEvent{}
+			q.pending = q.pending[1:]
+			q.stats.Dropped++
+		case OverflowDropNewest:
+			q.stats.Dropped++
+			accepted = false
+		case OverflowFail:
+			q.stats.Rejected++
+			q.mu.Unlock()
+			return ErrQueueFull
+		case OverflowBlock:
+			if propagated {
+				q.stats.Rejected++
+				q.mu.Unlock()
+				return ErrQueueFull
+			}
+			// Other producers may enqueue while this one waits, the event takes its sequence id afterwards
+			space := q.space
+			q.mu.Unlock()
+			q.sendMu.Unlock()
+			select {
+			case <-space:
+			case <-q.done:
+			case <-ctx.Done():
+				q.sendMu.Lock()
+				q.mu.Lock()
+				q.stats.Rejected++
+				q.mu.Unlock()
+				return ctx.Err()
+			}
+			q.sendMu.Lock()
+			q.mu.Lock()
+		}
+	}
 
 	if q.closed {
-		return fmt.Errorf("queue is closed")
+		q.mu.Unlock()
+		return ErrQueueClosed
+	}
+
+	if event.Seq == 0 && q.options.Validator != nil {
+		if err := q.options.Validator(event.Event); err != nil {
+			q.stats.Rejected++
+			q.mu.Unlock()
+			return err
+		}
+	}
+
+	if !accepted {
+		// The dropped event is not part of the stream, it takes no sequence id and stops here
+		q.mu.Unlock()
+		return nil
+	}
+
+	if event.Seq == 0 {
+		event.Seq = q.lastSeq + 1
+	}
+	if event.Seq > q.lastSeq {
+		q.lastSeq = event.Seq
 	}
+	q.retain(event)
+	q.pending = append(q.pending, event)
+	q.stats.Enqueued++
+	q.signal()
+	children := append([]*EventQueue(nil), q.children...)
+	q.mu.Unlock()
 
+	// Propagate to children
+	for _, child := range children {
+		if err := child.enqueue(ctx, event, true); err != nil && !errors.Is(err, ErrQueueClosed) {
+			// The child would silently miss the event, it is closed so its consumer sees the stream end
+			// and can tap again from the last event it received
+			child.Close()
+		}
+	}
+
+	return nil
+}
+
+// retain keeps the event for replay within the retention limit, the caller must hold q.mu
+func (q *EventQueue) retain(event QueueEvent) {
+	retention := q.options.Retention
+	if retention == 0 {
+		return
+	}
 	q.events = append(q.events, event)
+	if retention > 0 && len(q.events) > retention {
+		trimmed := make([]QueueEvent, retention)
+		copy(trimmed, q.events[len(q.events)-retention:])
+		q.events = trimmed
+	}
+}
+
+// replay returns the retained events with a sequence id greater than afterSeq, the caller must hold q.mu
+func (q *EventQueue) replay(afterSeq uint64) []QueueEvent {
+	events := make([]QueueEvent, 0)
+	for _, event := range q.events {
+		if event.Seq > afterSeq {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Replay returns the retained events with a sequence id greater than afterSeq, oldest first
+func (q *EventQueue) Replay(afterSeq uint64) []QueueEvent {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.replay(afterSeq)
+}
 
-	// 推送到热流 channel
+// LastSeq returns the sequence id of the last enqueued event, zero when nothing was enqueued
+func (q *EventQueue) LastSeq() uint64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.lastSeq
+}
+
+// signal wakes the delivery goroutine, the caller must hold q.mu
+func (q *EventQueue) signal() {
 	select {
-	case q.eventsCh <- event:
+	case q.wake <- struct{}{}:
 	default:
-		// 如果满了就丢弃，或可扩展为阻塞/扩容
 	}
+}
 
-	// Propagate to children
-	for _, child := range q.children {
-		child.EnqueueEvent(event)
+// deliver moves pending events to sequencedCh in order and closes it once the queue is closed and drained
+func (q *EventQueue) deliver() {
+	defer close(q.sequencedCh)
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 {
+			if q.closed {
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+			<-q.wake
+			q.mu.Lock()
+		}
+		event := q.pending[0]
+		q.pending[0] = QueueEvent{}
+		q.pending = q.pending[1:]
+		close(q.space)
+		q.space = make(chan struct{})
+		q.mu.Unlock()
+
+		q.sequencedCh <- event
 	}
+}
 
-	return nil
+// Sequenced returns a channel that emits events from this queue together with their sequence ids.
+// Use either Sequenced or AsFlux to consume a queue, not both.
+func (q *EventQueue) Sequenced() <-chan QueueEvent {
+	q.once.Do(func() {
+		go q.deliver()
+	})
+	return q.sequencedCh
 }
 
 // AsFlux returns a channel that emits events from this queue（热流）
 func (q *EventQueue) AsFlux() <-chan interface{} {
+	q.fluxOnce.Do(func() {
+		sequenced := q.Sequenced()
+		go func() {
+			defer close(q.eventsCh)
+			for event := range sequenced {
+				q.eventsCh <- event.Event
+			}
+		}()
+	})
 	return q.eventsCh
 }
 
-// Tap taps the event queue to create a new child queue that receives all future events
-func (q *EventQueue) Tap() (*EventQueue, error) {
+// Tap taps the event queue to create a new child queue that receives all future events.
+// The child inherits the options of this queue, opts override them.
+// Each child has its own delivery channel, so every subscriber receives every event;
+// closing the child detaches it from this queue.
+// A child never blocks the producer: when it is full under OverflowBlock, or rejects an event
+// under OverflowFail, it is closed, and its consumer can catch up with TapFrom.
+func (q *EventQueue) Tap(opts ...QueueOption) (*EventQueue, error) {
+	return q.tap(nil, opts...)
+}
+
+// TapFrom taps the event queue like Tap, first delivering the retained events with a
+// sequence id greater than afterSeq so the child misses nothing in between
+func (q *EventQueue) TapFrom(afterSeq uint64, opts ...QueueOption) (*EventQueue, error) {
+	return q.tap(&afterSeq, opts...)
+}
+
+// tap creates a child queue, replaying the retained events after afterSeq when it is set
+func (q *EventQueue) tap(afterSeq *uint64, opts ...QueueOption) (*EventQueue, error) {
+	// Waiting for the event being handed to the children keeps the child from missing it
+	q.sendMu.Lock()
+	defer q.sendMu.Unlock()
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	childQueue := NewEventQueue()
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+
+	childOpts := append([]QueueOption{
+		WithCapacity(q.options.Capacity),
+		WithOverflowPolicy(q.options.OverflowPolicy),
+		WithRetention(q.options.Retention),
+	}, opts...)
+	childQueue := NewEventQueue(childOpts...)
+	childQueue.parent = q
+	childQueue.lastSeq = q.lastSeq
+	if afterSeq != nil {
+		// Replayed events bypass the overflow policy, they are already bounded by the retention
+		for _, event := range q.replay(*afterSeq) {
+			childQueue.retain(event)
+			childQueue.pending = append(childQueue.pending, event)
+		}
+		childQueue.signal()
+	}
 	q.children = append(q.children, childQueue)
 	return childQueue, nil
 }
 
-// Close closes the queue for future push events
-func (q *EventQueue) Close() error {
+// removeChild detaches a child queue so it no longer receives events
+func (q *EventQueue) removeChild(child *EventQueue) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	for i, c := range q.children {
+		if c == child {
+			q.children = append(q.children[:i:i], q.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// Subscribers returns the number of child queues attached to this queue
+func (q *EventQueue) Subscribers() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return len(q.children)
+}
+
+// Close closes the queue for future push events and detaches it from its parent
+func (q *EventQueue) Close() error {
+	q.mu.Lock()
 	if q.closed {
+		q.mu.Unlock()
 		return nil
 	}
 
 	q.closed = true
-	q.once.Do(func() {
-		close(q.eventsCh)
-	})
+	close(q.done)
+	q.signal()
+	children := q.children
+	parent := q.parent
+	q.mu.Unlock()
+
+	if parent != nil {
+		parent.removeChild(q)
+	}
 
 	// Close all child queues
-	for _, child := range q.children {
+	for _, child := range children {
 		child.Close()
 	}
 
@@ -98,3 +491,17 @@ func (q *EventQueue) IsClosed() bool {
 	defer q.mu.RUnlock()
 	return q.closed
 }
+
+// Stats returns the traffic counters of the queue
+func (q *EventQueue) Stats() QueueStats {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	stats := q.stats
+	stats.Pending = len(q.pending)
+	return stats
+}
+
+// Dropped returns the number of events discarded by the overflow policy
+func (q *EventQueue) Dropped() uint64 {
+	return q.Stats().Dropped
+}
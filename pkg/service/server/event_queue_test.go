@@ -0,0 +1,257 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+// enqueueAll enqueues the events in order and fails the test on the first error
+func enqueueAll(t *testing.T, queue *server.EventQueue, events ...interface{}) {
+	t.Helper()
+	for _, event := range events {
+		if err := queue.EnqueueEvent(event); err != nil {
+			t.Fatalf("EnqueueEvent(%v) error = %v", event, err)
+		}
+	}
+}
+
+// drainQueue closes the queue and returns the events it delivers
+func drainQueue(queue *server.EventQueue) []server.QueueEvent {
+	queue.Close()
+	events := make([]server.QueueEvent, 0)
+	for event := range queue.Sequenced() {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestOverflowGrowKeepsEveryEvent(t *testing.T) {
+	queue := server.NewEventQueue(server.WithCapacity(2), server.WithOverflowPolicy(server.OverflowGrow))
+	enqueueAll(t, queue, "a", "b", "c", "d")
+
+	if stats := queue.Stats(); stats.Pending != 4 || stats.Enqueued != 4 || stats.Dropped != 0 {
+		t.Errorf("Stats() = %+v, want 4 pending and enqueued events", stats)
+	}
+	want := []server.QueueEvent{{Seq: 1, Event: "a"}, {Seq: 2, Event: "b"}, {Seq: 3, Event: "c"}, {Seq: 4, Event: "d"}}
+	if got := drainQueue(queue); !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestOverflowBlockWaitsForTheConsumer(t *testing.T) {
+	queue := server.NewEventQueue(server.WithCapacity(1), server.WithOverflowPolicy(server.OverflowBlock))
+	enqueueAll(t, queue, "a")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := queue.EnqueueEventContext(ctx, "rejected"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("EnqueueEventContext() on a full queue error = %v, want context.DeadlineExceeded", err)
+	}
+
+	enqueued := make(chan error, 1)
+	go func() {
+		enqueued <- queue.EnqueueEvent("b")
+	}()
+	select {
+	case err := <-enqueued:
+		t.Fatalf("EnqueueEvent() on a full queue returned %v before the consumer read", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	events := queue.Sequenced()
+	if event := <-events; event.Event != "a" {
+		t.Errorf("first event = %v, want a", event)
+	}
+	if err := <-enqueued; err != nil {
+		t.Fatalf("EnqueueEvent() error = %v", err)
+	}
+	if event := <-events; event.Seq != 2 || event.Event != "b" {
+		t.Errorf("second event = %+v, want b with sequence id 2", event)
+	}
+	if stats := queue.Stats(); stats.Rejected != 1 || stats.Enqueued != 2 {
+		t.Errorf("Stats() = %+v, want 2 enqueued and 1 rejected event", stats)
+	}
+}
+
+func TestOverflowBlockReleasesProducerOnClose(t *testing.T) {
+	queue := server.NewEventQueue(server.WithCapacity(1), server.WithOverflowPolicy(server.OverflowBlock))
+	enqueueAll(t, queue, "a")
+
+	enqueued := make(chan error, 1)
+	go func() {
+		enqueued <- queue.EnqueueEvent("b")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	queue.Close()
+
+	if err := <-enqueued; !errors.Is(err, server.ErrQueueClosed) {
+		t.Errorf("EnqueueEvent() error = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestOverflowDropOldestKeepsTheLatestEvents(t *testing.T) {
+	queue := server.NewEventQueue(server.WithCapacity(2), server.WithOverflowPolicy(server.OverflowDropOldest))
+	enqueueAll(t, queue, "a", "b", "c", "d")
+
+	if dropped := queue.Dropped(); dropped != 2 {
+		t.Errorf("Dropped() = %d, want 2", dropped)
+	}
+	want := []server.QueueEvent{{Seq: 3, Event: "c"}, {Seq: 4, Event: "d"}}
+	if got := drainQueue(queue); !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func TestOverflowDropNewestSkipsTheDroppedEvent(t *testing.T) {
+	queue := server.NewEventQueue(server.WithCapacity(2), server.WithOverflowPolicy(server.OverflowDropNewest))
+	tap, err := queue.Tap(server.WithOverflowPolicy(server.OverflowGrow))
+	if err != nil {
+		t.Fatalf("Tap() error = %v", err)
+	}
+	enqueueAll(t, queue, "a", "b", "dropped")
+
+	if dropped := queue.Dropped(); dropped != 1 {
+		t.Errorf("Dropped() = %d, want 1", dropped)
+	}
+	if lastSeq := queue.LastSeq(); lastSeq != 2 {
+		t.Errorf("LastSeq() = %d, want 2, the dropped event takes no sequence id", lastSeq)
+	}
+
+	// The next accepted event follows the last delivered one without a gap
+	events := queue.Sequenced()
+	<-events
+	enqueueAll(t, queue, "c")
+
+	want := []server.QueueEvent{{Seq: 1, Event: "a"}, {Seq: 2, Event: "b"}, {Seq: 3, Event: "c"}}
+	if got := drainQueue(tap); !reflect.DeepEqual(got, want) {
+		t.Errorf("tap delivered %v, want %v without the dropped event", got, want)
+	}
+}
+
+func TestOverflowFailRejectsTheEvent(t *testing.T) {
+	queue := server.NewEventQueue(server.WithCapacity(1), server.WithOverflowPolicy(server.OverflowFail))
+	enqueueAll(t, queue, "a")
+
+	if err := queue.EnqueueEvent("b"); !errors.Is(err, server.ErrQueueFull) {
+		t.Fatalf("EnqueueEvent() on a full queue error = %v, want ErrQueueFull", err)
+	}
+	if stats := queue.Stats(); stats.Rejected != 1 || stats.Enqueued != 1 {
+		t.Errorf("Stats() = %+v, want 1 enqueued and 1 rejected event", stats)
+	}
+	if lastSeq := queue.LastSeq(); lastSeq != 1 {
+		t.Errorf("LastSeq() = %d, want 1", lastSeq)
+	}
+}
+
+func TestSlowTapDoesNotBlockTheProducer(t *testing.T) {
+	for _, policy := range []server.OverflowPolicy{server.OverflowBlock, server.OverflowFail} {
+		t.Run(policy.String(), func(t *testing.T) {
+			queue := server.NewEventQueue(server.WithCapacity(1), server.WithOverflowPolicy(server.OverflowBlock))
+			slow, err := queue.Tap(server.WithOverflowPolicy(policy))
+			if err != nil {
+				t.Fatalf("Tap() error = %v", err)
+			}
+			fast, err := queue.Tap(server.WithOverflowPolicy(server.OverflowGrow))
+			if err != nil {
+				t.Fatalf("Tap() error = %v", err)
+			}
+
+			// The producer only waits for the consumer of its own queue
+			received := make(chan []server.QueueEvent)
+			go func() {
+				events := make([]server.QueueEvent, 0)
+				for event := range queue.Sequenced() {
+					events = append(events, event)
+				}
+				received <- events
+			}()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for _, event := range []string{"a", "b", "c"} {
+					if err := queue.EnqueueEvent(event); err != nil {
+						t.Errorf("EnqueueEvent(%s) error = %v", event, err)
+					}
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("EnqueueEvent() blocked on a tap nobody reads")
+			}
+
+			if !slow.IsClosed() {
+				t.Error("full tap is still open, want it closed")
+			}
+			if subscribers := queue.Subscribers(); subscribers != 1 {
+				t.Errorf("Subscribers() = %d, want only the tap keeping up", subscribers)
+			}
+			if got := drainQueue(slow); len(got) != 1 || got[0].Event != "a" {
+				t.Errorf("closed tap delivered %v, want the events buffered before it was full", got)
+			}
+			if got := drainQueue(fast); len(got) != 3 {
+				t.Errorf("tap keeping up delivered %v, want every event", got)
+			}
+			queue.Close()
+			if got := <-received; len(got) != 3 {
+				t.Errorf("queue delivered %v, want every event", got)
+			}
+		})
+	}
+}
+
+func TestTapFromReplaysRetainedEvents(t *testing.T) {
+	queue := server.NewEventQueue(server.WithRetention(2))
+	enqueueAll(t, queue, "a", "b", "c")
+
+	tap, err := queue.TapFrom(1)
+	if err != nil {
+		t.Fatalf("TapFrom() error = %v", err)
+	}
+	enqueueAll(t, queue, "d")
+
+	want := []server.QueueEvent{{Seq: 2, Event: "b"}, {Seq: 3, Event: "c"}, {Seq: 4, Event: "d"}}
+	if got := drainQueue(tap); !reflect.DeepEqual(got, want) {
+		t.Errorf("tap delivered %v, want %v", got, want)
+	}
+}
+
+func TestTapReceivesConcurrentEventsInSequenceOrder(t *testing.T) {
+	queue := server.NewEventQueue(server.WithRetention(0))
+	tap, err := queue.Tap()
+	if err != nil {
+		t.Fatalf("Tap() error = %v", err)
+	}
+
+	var producers sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			for j := 0; j < 200; j++ {
+				if err := queue.EnqueueEvent(j); err != nil {
+					t.Errorf("EnqueueEvent() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	producers.Wait()
+
+	events := drainQueue(tap)
+	if len(events) != 1600 {
+		t.Fatalf("tap delivered %d events, want 1600", len(events))
+	}
+	for i, event := range events {
+		if event.Seq != uint64(i+1) {
+			t.Fatalf("tap event %d has sequence id %d, want %d", i, event.Seq, i+1)
+		}
+	}
+	queue.Close()
+}
@@ -0,0 +1,710 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+// DefaultA2AServer implements the A2AServer interface
+type DefaultA2AServer struct {
+	taskManager   server.TaskManager
+	queueManager  server.QueueManager
+	agentExecutor server.AgentExecutor
+	agentCard     *model.AgentCard
+
+	cancelGracePeriod time.Duration
+	executionTimeout  time.Duration            // zero means no bound
+	skillTimeouts     map[string]time.Duration // execution timeouts by skill ID
+	pool              *WorkerPool              // bounds concurrent executions, nil for no bound
+	maxPriority       int                      // highest admission priority granted to a request
+	runs              map[string]*taskRun      // running executors by task ID
+	runsMu            sync.Mutex
+	taskLocks         map[string]*taskLock // serialize the requests starting or continuing a run, by task ID
+	taskLocksMu       sync.Mutex
+}
+
+// NewDefaultA2AServer creates a new instance of DefaultA2AServer
+func NewDefaultA2AServer(taskManager server.TaskManager, queueManager server.QueueManager, agentExecutor server.AgentExecutor, agentCard *model.AgentCard, opts ...ServerOption) server.A2AServer {
+	s := &DefaultA2AServer{
+		taskManager:       taskManager,
+		queueManager:      queueManager,
+		agentExecutor:     agentExecutor,
+		agentCard:         agentCard,
+		cancelGracePeriod: DefaultCancelGracePeriod,
+		maxPriority:       DefaultMaxPriority,
+		skillTimeouts:     make(map[string]time.Duration),
+		runs:              make(map[string]*taskRun),
+		taskLocks:         make(map[string]*taskLock),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// HandleMessage handles a message request
+func (s *DefaultA2AServer) HandleMessage(ctx context.Context, params *model.MessageSendParams) (*model.SendMessageResponse, error) {
+	// A blocking run is canceled by tasks/cancel or when the client goes away,
+	// the run of a non-blocking request outlives it
+	parent := ctx
+	if params != nil && !params.IsBlocking() {
+		parent = context.WithoutCancel(ctx)
+	}
+	start, err := s.beginRun(ctx, params, parent)
+	if err != nil {
+		return nil, err
+	}
+	taskCtx := start.taskCtx
+	if start.tap != nil {
+		return s.followContinuation(ctx, taskCtx, start.run, start.tap, params.IsBlocking())
+	}
+
+	// A non-blocking request gets the submitted task right away, the executor keeps running in the background
+	if !params.IsBlocking() {
+		var result model.SendMessageResponse = s.presentTask(taskCtx.Task, taskCtx.GetHistoryLength())
+		go s.executeInBackground(start)
+		return &result, nil
+	}
+
+	err = s.runToEnd(ctx, start)
+
+	// The agent answered with a direct message, the task was never worked on
+	if reply := start.run.directReply(); reply != nil {
+		if err := s.taskManager.DeleteTask(context.WithoutCancel(ctx), taskCtx.TaskID); err != nil {
+			log.Printf("Error deleting task %s answered with a message: %v", taskCtx.TaskID, err)
+		}
+		var result model.SendMessageResponse = replyMessage(reply)
+		return &result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result model.SendMessageResponse = s.presentTask(start.run.currentTask(), taskCtx.GetHistoryLength())
+	return &result, nil
+}
+
+// executeInBackground runs the executor of a non-blocking message/send in a server-managed goroutine,
+// applying every event to the task so clients can follow it with tasks/get or tasks/resubscribe
+func (s *DefaultA2AServer) executeInBackground(start *runStart) {
+	ctx := context.WithoutCancel(start.runCtx)
+	s.runToEnd(ctx, start)
+
+	// The client already holds the task, so a direct reply completes it
+	if reply := start.run.directReply(); reply != nil {
+		taskCtx := start.taskCtx
+		message := *reply
+		message.TaskID = taskCtx.TaskID
+		status := model.NewTaskStatus(model.TaskStateCompleted)
+		status.Message = &message
+		if _, err := s.taskManager.ApplyStatusUpdate(ctx, start.run.currentTask(), &model.TaskStatusUpdateEvent{
+			TaskID:    taskCtx.TaskID,
+			ContextID: taskCtx.ContextID,
+			Kind:      model.KindStatusUpdate,
+			Status:    status,
+			Final:     true,
+		}); err != nil {
+			log.Printf("Error completing task %s answered with a message: %v", taskCtx.TaskID, err)
+		}
+	}
+}
+
+// HandleMessageStream handles a streaming message request
+func (s *DefaultA2AServer) HandleMessageStream(ctx context.Context, params *model.MessageSendParams) (<-chan *model.SendStreamingMessageResponse, error) {
+	// The run is canceled by tasks/cancel, or when the client goes away before the final event.
+	// Once the final event is streamed, an executor waiting for input keeps running.
+	start, err := s.beginRun(ctx, params, context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, err
+	}
+	taskCtx, run, queue := start.taskCtx, start.run, start.queue
+	if start.tap != nil {
+		return s.streamContinuation(ctx, taskCtx, start.tap), nil
+	}
+
+	streamed := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			run.cancel()
+		case <-streamed:
+		case <-run.done:
+		}
+	}()
+
+	// Create response channel
+	responseChan := make(chan *model.SendStreamingMessageResponse)
+
+	// Start goroutine to handle streaming
+	go func() {
+		defer close(responseChan)
+
+		// The stream starts with the task as created, followed by the events of the agent.
+		// A direct reply of the agent is streamed alone, without the task.
+		var initial model.SendStreamingMessageResponse = s.presentTask(taskCtx.Task, taskCtx.GetHistoryLength())
+		// Once the client is gone the responses are dropped, the loop keeps applying the events of the run
+		deliver := func(response model.SendStreamingMessageResponse) {
+			select {
+			case responseChan <- &response:
+			case <-ctx.Done():
+			}
+		}
+		send := func(response model.SendStreamingMessageResponse) {
+			if task := initial; task != nil {
+				deliver(task)
+				initial = nil
+			}
+			if response != nil {
+				deliver(response)
+			}
+		}
+
+		// Execute the task concurrently so every event reaches the client as soon as it is produced.
+		// The execution result is reported before the queue is closed, so it is always
+		// available once the event loop below ends because of the closed queue.
+		// A failure is reported before its final event is enqueued, so the stream can end with both.
+		execErr := make(chan error, 1)
+		go func() {
+			err := s.execute(start.runCtx, start.slot, start.requestCtx, queue)
+			switch {
+			case timedOut(start.runCtx):
+				// The deadline already failed the task
+				execErr <- exception.NewExecutionTimeoutError(taskCtx.TaskID)
+			case start.runCtx.Err() != nil:
+				if _, cancelErr := s.persistCanceled(context.WithoutCancel(ctx), taskCtx.TaskID); cancelErr != nil {
+					log.Printf("Error marking task %s as canceled: %v", taskCtx.TaskID, cancelErr)
+				}
+				execErr <- nil
+			case err != nil:
+				failure := executionFailure(taskCtx.TaskID, err)
+				execErr <- failure
+				s.reportFailure(taskCtx, queue, failure)
+			default:
+				execErr <- nil
+			}
+			queue.Close()
+			if err := s.queueManager.Remove(ctx, taskCtx.TaskID, queue); err != nil {
+				log.Printf("Error removing queue for task %s: %v", taskCtx.TaskID, err)
+			}
+			s.finishRun(taskCtx.TaskID, run)
+		}()
+
+		// Stream the events of the agent as produced until the final event or the end of execution.
+		// The loop keeps applying the events produced after the final one, for example by an executor
+		// that reported input-required and continues once server.AwaitInput returns the follow-up message.
+		replied := false
+		observed := make(chan struct{})
+		var endObserve sync.Once
+		applied := s.applyEvents(context.WithoutCancel(ctx), run, queue, func(event server.QueueEvent) bool {
+			final := false
+			switch e := event.Event.(type) {
+			case *model.TaskStatusUpdateEvent:
+				send(server.NewSequencedResponse(e, event.Seq))
+				final = e.Final
+
+			case *model.TaskArtifactUpdateEvent:
+				send(server.NewSequencedResponse(e, event.Seq))
+				final = e.Final
+
+			case *model.Message:
+				if run.directReply() == e {
+					initial = nil
+					send(replyMessage(e))
+					replied = true
+					final = true
+				} else {
+					send(server.NewSequencedResponse(e, event.Seq))
+				}
+
+			case *model.Task:
+				// Skip task events
+
+			default:
+				// Skip unknown events
+				log.Printf("Unknown event type for task %s: %T", taskCtx.TaskID, e)
+			}
+			if final {
+				endObserve.Do(func() { close(observed) })
+			}
+			return !final
+		})
+		go func() {
+			<-applied
+			endObserve.Do(func() { close(observed) })
+		}()
+		<-observed
+
+		close(streamed)
+
+		if replied {
+			// The task was never worked on, it is dropped once the executor returns
+			go func() {
+				<-run.done
+				if err := s.taskManager.DeleteTask(context.WithoutCancel(ctx), taskCtx.TaskID); err != nil {
+					log.Printf("Error deleting task %s answered with a message: %v", taskCtx.TaskID, err)
+				}
+			}()
+			return
+		}
+
+		select {
+		case err := <-execErr:
+			if err != nil {
+				send(server.NewErrorResponse(err))
+			}
+		default:
+			// The final event arrived while the executor is still returning
+		}
+		// An execution without any event still streams the task
+		send(nil)
+
+		log.Printf("Task %s updates stream completed via handleMessageStream", taskCtx.TaskID)
+	}()
+
+	return responseChan, nil
+}
+
+// applyEvent applies an event of the run to the task of the run: a status or artifact update,
+// or a message joining the task history. Events of other types are left alone.
+func (s *DefaultA2AServer) applyEvent(ctx context.Context, run *taskRun, event server.QueueEvent) error {
+	defer run.markApplied(event.Seq)
+
+	task := run.currentTask()
+	var (
+		updatedTask *model.Task
+		err         error
+	)
+	switch e := event.Event.(type) {
+	case *model.TaskStatusUpdateEvent:
+		updatedTask, err = s.taskManager.ApplyStatusUpdate(ctx, task, e)
+	case *model.TaskArtifactUpdateEvent:
+		updatedTask, err = s.taskManager.ApplyArtifactUpdate(ctx, task, e)
+	case *model.Message:
+		if isSubmitted(task) {
+			// A message before any task update answers the request without a task
+			run.setReply(e)
+			return nil
+		}
+		updatedTask, err = s.taskManager.ApplyTaskUpdateSingle(ctx, task, e)
+	default:
+		return nil
+	}
+	if err != nil {
+		log.Printf("Error applying %T for task %s: %v", event.Event, run.taskID, err)
+		return err
+	}
+	run.setTask(updatedTask)
+	return nil
+}
+
+// isSubmitted reports whether the agent has not reported anything about a task yet
+func isSubmitted(task *model.Task) bool {
+	return task.Status != nil && task.Status.State == model.TaskStateSubmitted
+}
+
+// replyMessage returns a direct reply of the agent as sent to the client, detached from the dropped task
+func replyMessage(reply *model.Message) *model.Message {
+	message := *reply
+	message.TaskID = ""
+	return &message
+}
+
+// pausesRun reports whether an event ends the observation of a continued run:
+// a final event, or a status that finishes the task or asks the client for input again
+func pausesRun(event interface{}) bool {
+	switch e := event.(type) {
+	case *model.TaskStatusUpdateEvent:
+		if e.Final || e.Status == nil {
+			return e.Final
+		}
+		state := e.Status.State
+		return state.IsFinal() || state == model.TaskStateInputRequired || state == model.TaskStateAuthRequired
+	case *model.TaskArtifactUpdateEvent:
+		return e.Final
+	default:
+		return false
+	}
+}
+
+// observeContinuation calls handle with the events of a continued run, observed through tap,
+// until the run pauses, its queue is closed or ctx is done
+func observeContinuation(ctx context.Context, tap *server.EventQueue, handle func(server.QueueEvent)) error {
+	defer discard(tap)
+	events := tap.Sequenced()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			handle(event)
+			if pausesRun(event.Event) {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// followContinuation answers a message/send whose follow-up message was delivered to the running executor.
+// A blocking request waits until the run finishes the task or asks for input again, and gets the task as
+// updated by the event loop of the run.
+func (s *DefaultA2AServer) followContinuation(ctx context.Context, taskCtx *model.RequestContext, run *taskRun, tap *server.EventQueue, blocking bool) (*model.SendMessageResponse, error) {
+	if !blocking {
+		discard(tap)
+		var result model.SendMessageResponse = s.presentTask(taskCtx.Task, taskCtx.GetHistoryLength())
+		return &result, nil
+	}
+
+	var lastSeq uint64
+	err := observeContinuation(ctx, tap, func(event server.QueueEvent) {
+		lastSeq = event.Seq
+	})
+	if err == nil {
+		err = run.waitApplied(ctx, lastSeq)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for task %s: %w", taskCtx.TaskID, err)
+	}
+
+	task, err := s.taskManager.GetTask(ctx, taskCtx.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %w", taskCtx.TaskID, err)
+	}
+	var result model.SendMessageResponse = s.presentTask(task, taskCtx.GetHistoryLength())
+	return &result, nil
+}
+
+// streamContinuation answers a message/stream whose follow-up message was delivered to the running executor,
+// streaming the task and then the events of the run until it finishes the task or asks for input again
+func (s *DefaultA2AServer) streamContinuation(ctx context.Context, taskCtx *model.RequestContext, tap *server.EventQueue) <-chan *model.SendStreamingMessageResponse {
+	responseChan := make(chan *model.SendStreamingMessageResponse)
+	task := s.presentTask(taskCtx.Task, taskCtx.GetHistoryLength())
+	go func() {
+		defer close(responseChan)
+
+		send := func(response model.SendStreamingMessageResponse) {
+			select {
+			case responseChan <- &response:
+			case <-ctx.Done():
+			}
+		}
+		send(task)
+
+		err := observeContinuation(ctx, tap, func(event server.QueueEvent) {
+			switch e := event.Event.(type) {
+			case *model.TaskStatusUpdateEvent:
+				send(server.NewSequencedResponse(e, event.Seq))
+			case *model.TaskArtifactUpdateEvent:
+				send(server.NewSequencedResponse(e, event.Seq))
+			case *model.Message:
+				send(server.NewSequencedResponse(e, event.Seq))
+			}
+		})
+		if err != nil {
+			log.Printf("Client left the continued stream of task %s: %v", taskCtx.TaskID, err)
+		}
+	}()
+	return responseChan
+}
+
+// lifecycleValidator rejects queue events the task lifecycle does not allow, starting from the current
+// task state, and every event after a direct reply, so the executor gets a typed error from EnqueueEvent
+// instead of a silently ignored update
+func lifecycleValidator(task *model.Task) server.QueueOption {
+	var state model.TaskState
+	if task.Status != nil {
+		state = task.Status.State
+	}
+	replied := false
+	return server.WithValidator(func(event interface{}) error {
+		if replied {
+			return server.ErrReplied
+		}
+		switch e := event.(type) {
+		case *model.TaskStatusUpdateEvent:
+			if e.Status == nil {
+				return nil
+			}
+			if state.IsFinal() && state == e.Status.State {
+				return nil
+			}
+			if state != "" && !state.CanTransitionTo(e.Status.State) {
+				return exception.NewInvalidTransitionError(task.ID, state, e.Status.State)
+			}
+			state = e.Status.State
+		case *model.TaskArtifactUpdateEvent:
+			if state.IsFinal() {
+				return exception.NewTaskTerminalError(task.ID, state)
+			}
+		case *model.Message:
+			// A message before any task update is the direct reply of the agent, which ends the exchange
+			replied = state == model.TaskStateSubmitted
+		}
+		return nil
+	})
+}
+
+// GetTask gets a task by ID
+func (s *DefaultA2AServer) GetTask(ctx context.Context, taskID string) (*model.Task, error) {
+	task, err := s.taskManager.GetTask(ctx, taskID)
+	if err != nil || task == nil {
+		return task, err
+	}
+	return s.presentTask(task, nil), nil
+}
+
+// QueryTask gets a task for tasks/get, with its history limited to the requested historyLength
+func (s *DefaultA2AServer) QueryTask(ctx context.Context, params *model.TaskQueryParams) (*model.Task, error) {
+	if params == nil {
+		return nil, fmt.Errorf("params cannot be nil")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, exception.NewInvalidParamsError(err)
+	}
+	task, err := s.taskManager.QueryTask(ctx, params)
+	if err != nil || task == nil {
+		return task, err
+	}
+	return s.presentTask(task, nil), nil
+}
+
+// presentTask returns the task as exposed to clients: a copy detached from the stored task, with at most
+// historyLength history messages and without its status history unless the agent card advertises the
+// stateTransitionHistory capability
+func (s *DefaultA2AServer) presentTask(task *model.Task, historyLength *int) *model.Task {
+	presented := *task
+	presented.History = task.RecentHistory(historyLength)
+	presented.Artifacts = append(make([]*model.Artifact, 0, len(task.Artifacts)), task.Artifacts...)
+	presented.StatusHistory = nil
+	if s.agentCard != nil && s.agentCard.Capabilities != nil && s.agentCard.Capabilities.StateTransitionHistory {
+		presented.StatusHistory = append([]*model.TaskStatusTransition(nil), task.StatusHistory...)
+	}
+	return &presented
+}
+
+// CancelTask cancels a task.
+// A task in a final state is rejected with a TaskNotCancelable error; otherwise the canceled status is
+// persisted before the running executor is canceled, so a racing completion cannot override it.
+func (s *DefaultA2AServer) CancelTask(ctx context.Context, taskID string) (*model.Task, error) {
+	// No request starts or continues the run of the task while it is canceled
+	unlock := s.lockTask(taskID)
+	defer unlock()
+
+	canceledTask, err := s.cancelThroughQueue(ctx, taskID)
+	if err == nil && canceledTask == nil {
+		// No run streams the task, the task manager decides alone
+		canceledTask, err = s.taskManager.CancelTask(ctx, taskID)
+	}
+	if err != nil {
+		log.Printf("Error cancelling task %s: %v", taskID, err)
+		return nil, err
+	}
+
+	// Let the agent release its own resources
+	if err := s.agentExecutor.Cancel(ctx, taskID); err != nil {
+		log.Printf("Error cancelling task %s in the agent executor: %v", taskID, err)
+	}
+
+	// Cancel the running executor and wait a bounded grace period for it to return
+	s.stopRun(taskID)
+
+	log.Printf("Task %s cancelled successfully", taskID)
+	return s.presentTask(canceledTask, nil), nil
+}
+
+// cancelThroughQueue cancels a running task by enqueuing its canceled status to the queue of the run,
+// so the lifecycle validator of the queue decides between the cancellation and a racing final event of
+// the executor, and stream subscribers see the same final event the task keeps. Once the event loop of
+// the run applied the canceled status, the canceled task is returned.
+// It returns a nil task and no error when the task has no live queue.
+func (s *DefaultA2AServer) cancelThroughQueue(ctx context.Context, taskID string) (*model.Task, error) {
+	s.runsMu.Lock()
+	run, ok := s.runs[taskID]
+	s.runsMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	queue, err := s.queueManager.Get(ctx, taskID)
+	if err != nil || queue == nil {
+		return nil, nil
+	}
+	// Tap before enqueuing so the sequence id of the canceled status can be read back
+	tap, err := queue.Tap(server.WithOverflowPolicy(server.OverflowGrow))
+	if err != nil {
+		return nil, nil
+	}
+	defer discard(tap)
+
+	task := run.currentTask()
+	canceled := &model.TaskStatusUpdateEvent{
+		TaskID:    taskID,
+		ContextID: task.ContextID,
+		Kind:      model.KindStatusUpdate,
+		Status:    model.NewTaskStatus(model.TaskStateCanceled),
+		Final:     true,
+		Metadata:  map[string]interface{}{model.StatusReasonKey: "canceled by client request"},
+	}
+	if err := queue.EnqueueEvent(canceled); err != nil {
+		var invalid *exception.InvalidTransitionError
+		if errors.As(err, &invalid) {
+			// The executor already finished the task
+			return nil, exception.NewTaskNotCancelableError(taskID, invalid.From.String())
+		}
+		if errors.Is(err, server.ErrQueueClosed) || errors.Is(err, server.ErrReplied) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to cancel task %s: %w", taskID, err)
+	}
+
+	for event := range tap.Sequenced() {
+		if event.Event == canceled {
+			if err := run.waitApplied(ctx, event.Seq); err != nil {
+				return nil, fmt.Errorf("failed to wait for task %s: %w", taskID, err)
+			}
+			break
+		}
+	}
+	return s.persistCanceled(ctx, taskID)
+}
+
+// SetTaskPushNotification sets the push notification configuration for a task
+func (s *DefaultA2AServer) SetTaskPushNotification(ctx context.Context, taskID string, config *model.TaskPushNotificationConfig) (*model.TaskPushNotificationConfig, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetTaskPushNotification gets the push notification configuration for a task
+func (s *DefaultA2AServer) GetTaskPushNotification(ctx context.Context, taskID string) (*model.TaskPushNotificationConfig, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// SubscribeToTaskUpdates subscribes to task updates
+func (s *DefaultA2AServer) SubscribeToTaskUpdates(ctx context.Context, params *model.TaskQueryParams) (<-chan *model.SendStreamingMessageResponse, error) {
+	if params == nil {
+		return nil, fmt.Errorf("params cannot be nil")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, exception.NewInvalidParamsError(err)
+	}
+	taskID := params.TaskID
+
+	// Get task
+	task, err := s.taskManager.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if task == nil {
+		log.Printf("Task with ID %s not found for subscription", taskID)
+		return nil, fmt.Errorf("task with ID %s not found for subscription", taskID)
+	}
+
+	// Tap the task's queue so this subscriber receives every event independently of the other consumers,
+	// starting with the retained events after the offset the subscriber resumes from
+	var queue *server.EventQueue
+	if offset, ok := params.GetOffset(); ok {
+		queue, err = s.queueManager.TapFrom(ctx, taskID, offset)
+	} else {
+		queue, err = s.queueManager.Tap(ctx, taskID)
+	}
+	if err != nil || queue == nil {
+		// No run is streaming the task any more, the stored task carries its latest status
+		log.Printf("Task %s has no live queue, replying with the stored task: %v", taskID, err)
+		return s.replyWithStoredTask(ctx, params)
+	}
+
+	responseChan := make(chan *model.SendStreamingMessageResponse)
+
+	go func() {
+		defer close(responseChan)
+		// Detach from the task's queue and discard what is left once the subscriber is gone
+		defer func() {
+			queue.Close()
+			for range queue.Sequenced() {
+			}
+		}()
+
+		// Closing the tap ends the loop below when the subscriber leaves while no event arrives
+		stop := context.AfterFunc(ctx, func() { queue.Close() })
+		defer stop()
+
+		log.Printf("Subscriber attached to task %s updates via subscribeToTaskUpdates", taskID)
+
+		// Forward events from queue to response channel
+		for event := range queue.Sequenced() {
+			// Convert event to SendStreamingMessageResponse
+			var response model.SendStreamingMessageResponse
+			switch e := event.Event.(type) {
+			case *model.TaskStatusUpdateEvent:
+				response = e
+			case *model.TaskArtifactUpdateEvent:
+				response = e
+			case *model.Message:
+				response = e
+			case *model.Task:
+				response = e
+			default:
+				log.Printf("Unknown event type for task %s: %T", taskID, e)
+				continue
+			}
+
+			// Send response
+			response = server.NewSequencedResponse(response, event.Seq)
+			select {
+			case responseChan <- &response:
+			case <-ctx.Done():
+				log.Printf("Subscriber detached from task %s updates: %v", taskID, ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return responseChan, nil
+}
+
+// replyWithStoredTask streams the stored task alone, for a subscriber of a task without a live queue
+func (s *DefaultA2AServer) replyWithStoredTask(ctx context.Context, params *model.TaskQueryParams) (<-chan *model.SendStreamingMessageResponse, error) {
+	task, err := s.taskManager.QueryTask(ctx, params)
+	if err != nil || task == nil {
+		return nil, fmt.Errorf("task with ID %s not found for subscription: %w", params.TaskID, err)
+	}
+
+	responseChan := make(chan *model.SendStreamingMessageResponse, 1)
+	var response model.SendStreamingMessageResponse = task
+	responseChan <- &response
+	close(responseChan)
+	return responseChan, nil
+}
+
+// GetSelfAgentCard retrieves the AgentCard for this server
+func (s *DefaultA2AServer) GetSelfAgentCard() *model.AgentCard {
+	return s.agentCard
+}
+
+// GetAuthenticatedExtendedCard retrieves the authenticated extended AgentCard for the server.
+// By default, this returns the same card as getSelfAgentCard().
+// Subclasses can override this method to provide more detailed information
+// for authenticated clients.
+func (s *DefaultA2AServer) GetAuthenticatedExtendedCard(ctx context.Context) (*model.AgentCard, error) {
+	// TODO: Implement logic to return a more detailed AgentCard for authenticated clients.
+	// This may involve:
+	// 1. Checking the authenticated principal (if applicable through security context).
+	// 2. Loading a different AgentCard configuration or modifying the existing one.
+	// 3. Adding skills or details that are not available in the public AgentCard.
+	// For now, it returns the same card as getSelfAgentCard().
+	return s.agentCard, nil
+}
+
+// ListTasks returns all tasks
+func (s *DefaultA2AServer) ListTasks(ctx context.Context) ([]*model.Task, error) {
+	return s.taskManager.ListTasks(ctx)
+}
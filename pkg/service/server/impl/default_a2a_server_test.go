@@ -0,0 +1,645 @@
+package impl_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+	"github.com/a2ap/a2ago/pkg/service/server/impl"
+)
+
+// testAgent runs execute for every request, the server only calls Execute and Cancel
+type testAgent struct {
+	server.AgentExecutor
+	execute func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error
+	cancel  func(taskID string) // optional, called by Cancel
+}
+
+func (a *testAgent) Execute(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+	return a.execute(ctx, requestCtx, queue)
+}
+
+func (a *testAgent) Cancel(ctx context.Context, taskID string) error {
+	if a.cancel != nil {
+		a.cancel(taskID)
+	}
+	return nil
+}
+
+// newServer creates a DefaultA2AServer with in-memory managers whose agent runs execute
+func newServer(execute func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error, opts ...impl.ServerOption) server.A2AServer {
+	return newAgentServer(&testAgent{execute: execute}, opts...)
+}
+
+// newAgentServer creates a DefaultA2AServer with in-memory managers for agent
+func newAgentServer(agent *testAgent, opts ...impl.ServerOption) server.A2AServer {
+	card := &model.AgentCard{Name: "test", Capabilities: model.NewAgentCapabilities(true, false, true)}
+	return impl.NewDefaultA2AServer(impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore()),
+		impl.NewInMemoryQueueManager(), agent, card, opts...)
+}
+
+// newMessage creates the params of a user message for taskID
+func newMessage(taskID, text string) *model.MessageSendParams {
+	return model.NewMessageSendParams(model.NewUserMessage(taskID, "", []model.Part{model.NewTextPart(text)}), nil)
+}
+
+// sendInBackground sends a non-blocking message for taskID and returns the submitted task
+func sendInBackground(t *testing.T, s server.A2AServer, params *model.MessageSendParams) *model.Task {
+	t.Helper()
+	blocking := false
+	params.Configuration = &model.MessageSendConfiguration{Blocking: &blocking}
+	response, err := s.HandleMessage(context.Background(), params)
+	if err != nil {
+		t.Fatalf("HandleMessage(%s) error = %v", params.Message.TaskID, err)
+	}
+	return (*response).(*model.Task)
+}
+
+// waitForState polls the task until it reaches state and returns it
+func waitForState(t *testing.T, s server.A2AServer, taskID string, state model.TaskState) *model.Task {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err := s.GetTask(context.Background(), taskID)
+		if err != nil {
+			t.Fatalf("GetTask(%s) error = %v", taskID, err)
+		}
+		if task != nil && task.Status.State == state {
+			return task
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task %s = %+v, want %s", taskID, task, state)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// workUntilCanceled reports working and returns once the run is canceled, closing canceled
+func workUntilCanceled(canceled chan<- struct{}) func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+	return func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		if err := server.NewTaskUpdaterForRequest(requestCtx, queue).StartWork(nil); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	}
+}
+
+func TestCancelTaskCancelsRunningExecutor(t *testing.T) {
+	canceled := make(chan struct{})
+	s := newServer(workUntilCanceled(canceled))
+	sendInBackground(t, s, newMessage("task-cancel", "hello"))
+	waitForState(t, s, "task-cancel", model.TaskStateWorking)
+
+	task, err := s.CancelTask(context.Background(), "task-cancel")
+	if err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	if task.Status.State != model.TaskStateCanceled {
+		t.Errorf("CancelTask() = %s, want canceled", task.Status.State)
+	}
+	select {
+	case <-canceled:
+	default:
+		t.Error("CancelTask() returned before the executor context was canceled")
+	}
+	waitForState(t, s, "task-cancel", model.TaskStateCanceled)
+}
+
+func TestStreamDisconnectCancelsRunningExecutor(t *testing.T) {
+	canceled := make(chan struct{})
+	s := newServer(workUntilCanceled(canceled))
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	stream, err := s.HandleMessageStream(ctx, newMessage("task-disconnect", "hello"))
+	if err != nil {
+		t.Fatalf("HandleMessageStream() error = %v", err)
+	}
+	for response := range stream {
+		if sequenced, ok := (*response).(*server.SequencedResponse); ok {
+			if event, ok := sequenced.Response.(*model.TaskStatusUpdateEvent); ok && event.Status.State == model.TaskStateWorking {
+				break
+			}
+		}
+	}
+	disconnect()
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("executor context was not canceled after the client disconnected")
+	}
+	waitForState(t, s, "task-disconnect", model.TaskStateCanceled)
+}
+
+func TestCancelTaskWinsOverLaterCompletion(t *testing.T) {
+	completed := make(chan error, 1)
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		<-ctx.Done()
+		// The agent ignores the cancellation and reports completion anyway
+		completed <- server.NewTaskUpdaterForRequest(requestCtx, queue).Complete(nil)
+		return nil
+	})
+	sendInBackground(t, s, newMessage("task-race", "hello"))
+	if _, err := s.CancelTask(context.Background(), "task-race"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+
+	// The late completion is rejected with a typed error the executor can inspect
+	var transitionErr *exception.InvalidTransitionError
+	if err := <-completed; !errors.As(err, &transitionErr) || transitionErr.From != model.TaskStateCanceled {
+		t.Errorf("Complete() after CancelTask() error = %v, want InvalidTransitionError from canceled", err)
+	}
+	waitForState(t, s, "task-race", model.TaskStateCanceled)
+
+	if _, err := s.CancelTask(context.Background(), "task-race"); !exception.HasCode(err, exception.TaskNotCancelable) {
+		t.Errorf("CancelTask() of a canceled task error = %v, want TaskNotCancelable", err)
+	}
+}
+
+func TestCanceledTransitionRecordsReason(t *testing.T) {
+	s := newServer(workUntilCanceled(make(chan struct{})))
+	sendInBackground(t, s, newMessage("task-cancel-reason", "hello"))
+	waitForState(t, s, "task-cancel-reason", model.TaskStateWorking)
+	if _, err := s.CancelTask(context.Background(), "task-cancel-reason"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+
+	task, err := s.GetTask(context.Background(), "task-cancel-reason")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	last := task.StatusHistory[len(task.StatusHistory)-1]
+	if last.From != model.TaskStateWorking || last.State != model.TaskStateCanceled || last.Reason == "" {
+		t.Errorf("last transition = %+v, want working -> canceled with a reason", last)
+	}
+}
+
+// unwrap returns a streamed response without its sequence id
+func unwrap(response *model.SendStreamingMessageResponse) model.SendStreamingMessageResponse {
+	if sequenced, ok := (*response).(*server.SequencedResponse); ok {
+		return sequenced.Response
+	}
+	return *response
+}
+
+// collect reads a stream to its end and returns the responses without their sequence ids
+func collect(stream <-chan *model.SendStreamingMessageResponse) []model.SendStreamingMessageResponse {
+	responses := make([]model.SendStreamingMessageResponse, 0)
+	for response := range stream {
+		responses = append(responses, unwrap(response))
+	}
+	return responses
+}
+
+// lastState returns the task state last reported by the responses of a stream
+func lastState(responses []model.SendStreamingMessageResponse) model.TaskState {
+	var state model.TaskState
+	for _, response := range responses {
+		switch r := response.(type) {
+		case *model.Task:
+			state = r.Status.State
+		case *model.TaskStatusUpdateEvent:
+			state = r.Status.State
+		}
+	}
+	return state
+}
+
+// askThenEcho asks for input and completes the task echoing the follow-up message it awaits
+func askThenEcho(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+	updater := server.NewTaskUpdaterForRequest(requestCtx, queue)
+	if err := updater.RequireInput(nil); err != nil {
+		return err
+	}
+	message, err := server.AwaitInput(ctx)
+	if err != nil {
+		return err
+	}
+	return updater.Complete(updater.NewAgentMessage(message.Parts))
+}
+
+func TestFollowUpMessageReinvokesExecutor(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		updater := server.NewTaskUpdaterForRequest(requestCtx, queue)
+		if len(requestCtx.Task.History) < 2 {
+			return updater.RequireInput(nil)
+		}
+		return updater.Complete(updater.NewAgentMessage(requestCtx.Message.Parts))
+	})
+
+	response, err := s.HandleMessage(context.Background(), newMessage("task-multi-turn", "book a flight"))
+	if err != nil {
+		t.Fatalf("HandleMessage() error = %v", err)
+	}
+	if state := (*response).(*model.Task).Status.State; state != model.TaskStateInputRequired {
+		t.Fatalf("HandleMessage() state = %s, want input-required", state)
+	}
+	response, err = s.HandleMessage(context.Background(), newMessage("task-multi-turn", "to Paris"))
+	if err != nil {
+		t.Fatalf("follow-up HandleMessage() error = %v", err)
+	}
+
+	task := (*response).(*model.Task)
+	if task.Status.State != model.TaskStateCompleted {
+		t.Fatalf("follow-up HandleMessage() state = %s, want completed", task.Status.State)
+	}
+	var texts []string
+	for _, message := range task.History {
+		texts = append(texts, message.Role.String()+":"+message.Parts[0].(*model.TextPart).Text)
+	}
+	if got, want := strings.Join(texts, ","), "user:book a flight,user:to Paris,agent:to Paris"; got != want {
+		t.Errorf("history = %s, want %s", got, want)
+	}
+}
+
+func TestFollowUpMessageReachesAwaitingExecutor(t *testing.T) {
+	var calls int32
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		atomic.AddInt32(&calls, 1)
+		return askThenEcho(ctx, requestCtx, queue)
+	})
+	sendInBackground(t, s, newMessage("task-await", "book a flight"))
+	waitForState(t, s, "task-await", model.TaskStateInputRequired)
+
+	response, err := s.HandleMessage(context.Background(), newMessage("task-await", "to Paris"))
+	if err != nil {
+		t.Fatalf("follow-up HandleMessage() error = %v", err)
+	}
+	task := (*response).(*model.Task)
+	if task.Status.State != model.TaskStateCompleted {
+		t.Fatalf("follow-up HandleMessage() state = %s, want completed", task.Status.State)
+	}
+	if last := task.History[len(task.History)-1]; last.Role != model.RoleAgent || last.Parts[0].(*model.TextPart).Text != "to Paris" {
+		t.Errorf("last message = %+v, want the agent echoing the follow-up message", last)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Execute() called %d times, want 1", got)
+	}
+}
+
+func TestStreamedFollowUpReachesAwaitingExecutor(t *testing.T) {
+	s := newServer(askThenEcho)
+
+	stream, err := s.HandleMessageStream(context.Background(), newMessage("task-stream-await", "book a flight"))
+	if err != nil {
+		t.Fatalf("HandleMessageStream() error = %v", err)
+	}
+	if state := lastState(collect(stream)); state != model.TaskStateInputRequired {
+		t.Fatalf("first stream ended in %s, want input-required", state)
+	}
+
+	// The executor keeps waiting for input after the first stream is closed
+	stream, err = s.HandleMessageStream(context.Background(), newMessage("task-stream-await", "to Paris"))
+	if err != nil {
+		t.Fatalf("follow-up HandleMessageStream() error = %v", err)
+	}
+	if state := lastState(collect(stream)); state != model.TaskStateCompleted {
+		t.Fatalf("follow-up stream ended in %s, want completed", state)
+	}
+}
+
+func TestExecutorPanicFailsTask(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		panic("secret connection string")
+	})
+
+	_, err := s.HandleMessage(context.Background(), newMessage("task-panic", "hello"))
+	var a2aErr *exception.A2AError
+	if !errors.As(err, &a2aErr) || a2aErr.Code != exception.AgentExecutionError {
+		t.Fatalf("HandleMessage() error = %v, want AgentExecutionError", err)
+	}
+	if strings.Contains(a2aErr.Message, "secret") {
+		t.Errorf("error message %q exposes the panic value", a2aErr.Message)
+	}
+
+	task := waitForState(t, s, "task-panic", model.TaskStateFailed)
+	if task.Status.Error == "" || strings.Contains(task.Status.Error, "secret") {
+		t.Errorf("GetTask() status = %+v, want failed with a sanitized error", task.Status)
+	}
+}
+
+func TestExecutorErrorEndsStreamWithFailure(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		if err := server.NewTaskUpdaterForRequest(requestCtx, queue).StartWork(nil); err != nil {
+			return err
+		}
+		return exception.NewA2AErrorWithAll("Quota exceeded", exception.AgentExecutionError, nil, requestCtx.TaskID)
+	})
+
+	stream, err := s.HandleMessageStream(context.Background(), newMessage("task-stream-error", "hello"))
+	if err != nil {
+		t.Fatalf("HandleMessageStream() error = %v", err)
+	}
+	responses := collect(stream)
+	if state := lastState(responses); state != model.TaskStateFailed {
+		t.Errorf("last streamed state = %s, want failed", state)
+	}
+	end, ok := responses[len(responses)-1].(*server.ErrorResponse)
+	var a2aErr *exception.A2AError
+	if !ok || !errors.As(end.Err, &a2aErr) || a2aErr.Code != exception.AgentExecutionError || a2aErr.Message != "Quota exceeded" {
+		t.Errorf("stream end = %+v, want AgentExecutionError \"Quota exceeded\"", responses[len(responses)-1])
+	}
+}
+
+func TestBackgroundExecutorPanicFailsTask(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		var updater *server.TaskUpdater
+		return updater.StartWork(nil)
+	})
+
+	sendInBackground(t, s, newMessage("task-background-panic", "hello"))
+	waitForState(t, s, "task-background-panic", model.TaskStateFailed)
+}
+
+func TestExecutionTimeoutFailsTask(t *testing.T) {
+	canceled := make(chan struct{})
+	s := newServer(workUntilCanceled(canceled), impl.WithExecutionTimeout(50*time.Millisecond))
+
+	response, err := s.HandleMessage(context.Background(), newMessage("task-timeout", "hello"))
+	if err != nil {
+		t.Fatalf("HandleMessage() error = %v, want the failed task", err)
+	}
+	<-canceled
+
+	returned := (*response).(*model.Task)
+	stored := waitForState(t, s, "task-timeout", model.TaskStateFailed)
+	for name, task := range map[string]*model.Task{"HandleMessage()": returned, "GetTask()": stored} {
+		if task.Status.State != model.TaskStateFailed || task.Status.Metadata[model.StatusReasonKey] != model.StatusReasonTimeout {
+			t.Errorf("%s status = %+v, want failed with the timeout reason", name, task.Status)
+		}
+		if last := task.StatusHistory[len(task.StatusHistory)-1]; last.Reason != model.StatusReasonTimeout {
+			t.Errorf("%s last transition reason = %q, want %q", name, last.Reason, model.StatusReasonTimeout)
+		}
+	}
+}
+
+// contextStore is a task store refusing to save with a done context, like a store calling a database
+type contextStore struct {
+	server.TaskStore
+}
+
+func (s *contextStore) Save(ctx context.Context, task *model.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.TaskStore.Save(ctx, task)
+}
+
+func TestBackgroundTimeoutIsPersisted(t *testing.T) {
+	card := &model.AgentCard{Name: "test", Capabilities: model.NewAgentCapabilities(true, false, true)}
+	s := impl.NewDefaultA2AServer(impl.NewInMemoryTaskManager(&contextStore{impl.NewInMemoryTaskStore()}),
+		impl.NewInMemoryQueueManager(), &testAgent{execute: workUntilCanceled(make(chan struct{}))}, card,
+		impl.WithExecutionTimeout(50*time.Millisecond))
+
+	sendInBackground(t, s, newMessage("task-background-timeout", "hello"))
+	task := waitForState(t, s, "task-background-timeout", model.TaskStateFailed)
+	if task.Status.Metadata[model.StatusReasonKey] != model.StatusReasonTimeout {
+		t.Errorf("GetTask() status = %+v, want the timeout reason", task.Status)
+	}
+}
+
+func TestSkillTimeoutAndClientDeadline(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, impl.WithSkillTimeout("quick", 50*time.Millisecond))
+
+	quick := newMessage("task-quick-skill", "hello")
+	quick.SetSkillID("quick")
+	expired := newMessage("task-client-deadline", "hello")
+	expired.SetDeadline(time.Now().Add(-time.Second))
+
+	for _, params := range []*model.MessageSendParams{quick, expired} {
+		sendInBackground(t, s, params)
+		task := waitForState(t, s, params.Message.TaskID, model.TaskStateFailed)
+		if task.Status.Metadata[model.StatusReasonKey] != model.StatusReasonTimeout {
+			t.Errorf("task %s status = %+v, want the timeout reason", task.ID, task.Status)
+		}
+	}
+}
+
+func TestWorkerPoolRejectsWhenFull(t *testing.T) {
+	started := make(chan string, 4)
+	pool := impl.NewWorkerPool(1, 1)
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		started <- requestCtx.TaskID
+		<-ctx.Done()
+		return ctx.Err()
+	}, impl.WithWorkerPool(pool))
+
+	sendInBackground(t, s, newMessage("task-running", "hello"))
+	sendInBackground(t, s, newMessage("task-pending", "hello"))
+	if got := <-started; got != "task-running" {
+		t.Fatalf("first started task = %s, want task-running", got)
+	}
+
+	rejected := newMessage("task-rejected", "hello")
+	blocking := false
+	rejected.Configuration = &model.MessageSendConfiguration{Blocking: &blocking}
+	if _, err := s.HandleMessage(context.Background(), rejected); !exception.HasCode(err, exception.ServerBusy) {
+		t.Fatalf("HandleMessage(task-rejected) error = %v, want ServerBusy", err)
+	}
+	if task, err := s.GetTask(context.Background(), "task-rejected"); err == nil && task != nil {
+		t.Errorf("GetTask(task-rejected) = %+v, want the rejected task not to be created", task)
+	}
+	if stats := pool.Stats(); stats.Running != 1 || stats.Pending != 1 || stats.Rejected != 1 {
+		t.Errorf("Stats() = %+v, want 1 running, 1 pending and 1 rejected", stats)
+	}
+
+	if _, err := s.CancelTask(context.Background(), "task-running"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	if got := <-started; got != "task-pending" {
+		t.Fatalf("next started task = %s, want task-pending", got)
+	}
+	if stats := pool.Stats(); stats.Admitted != 2 || stats.Pending != 0 || stats.MaxWait <= 0 {
+		t.Errorf("Stats() = %+v, want 2 admitted after a wait and none pending", stats)
+	}
+	if _, err := s.CancelTask(context.Background(), "task-pending"); err != nil {
+		t.Fatalf("CancelTask(task-pending) error = %v", err)
+	}
+}
+
+func TestDirectMessageReplyLeavesNoTask(t *testing.T) {
+	refused := make(chan error, 2)
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		updater := server.NewTaskUpdaterForRequest(requestCtx, queue)
+		if err := updater.Reply([]model.Part{model.NewTextPart("pong")}); err != nil {
+			return err
+		}
+		refused <- updater.StartWork(nil)
+		return nil
+	})
+
+	response, err := s.HandleMessage(context.Background(), newMessage("task-direct", "ping"))
+	if err != nil {
+		t.Fatalf("HandleMessage() error = %v", err)
+	}
+	reply, ok := (*response).(*model.Message)
+	if !ok || reply.TaskID != "" || reply.Role != model.RoleAgent || reply.Parts[0].(*model.TextPart).Text != "pong" {
+		t.Fatalf("HandleMessage() = %#v, want the agent message without a task", *response)
+	}
+	if task, err := s.GetTask(context.Background(), "task-direct"); err == nil && task != nil {
+		t.Errorf("GetTask() = %+v, want no task left", task)
+	}
+
+	stream, err := s.HandleMessageStream(context.Background(), newMessage("task-direct-stream", "ping"))
+	if err != nil {
+		t.Fatalf("HandleMessageStream() error = %v", err)
+	}
+	streamed := collect(stream)
+	if len(streamed) != 1 {
+		t.Fatalf("HandleMessageStream() streamed %d values, want only the reply", len(streamed))
+	}
+	if message, ok := streamed[0].(*model.Message); !ok || message.TaskID != "" {
+		t.Errorf("HandleMessageStream() value = %#v, want the agent message without a task", streamed[0])
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err := s.GetTask(context.Background(), "task-direct-stream")
+		if err != nil || task == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("streamed task is still stored in state %s", task.Status.State)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-refused; !errors.Is(err, server.ErrReplied) {
+			t.Errorf("StartWork() after Reply() error = %v, want ErrReplied", err)
+		}
+	}
+}
+
+func TestBackgroundDirectMessageCompletesTask(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		return server.NewTaskUpdaterForRequest(requestCtx, queue).Reply([]model.Part{model.NewTextPart("pong")})
+	})
+
+	sendInBackground(t, s, newMessage("task-direct-background", "ping"))
+	task := waitForState(t, s, "task-direct-background", model.TaskStateCompleted)
+	if message := task.Status.Message; message == nil || message.Parts[0].(*model.TextPart).Text != "pong" {
+		t.Errorf("completed status = %+v, want the reply as its message", task.Status)
+	}
+}
+
+func TestSubscriberLeavingIdleTaskEndsSubscription(t *testing.T) {
+	canceled := make(chan struct{})
+	s := newServer(workUntilCanceled(canceled))
+	sendInBackground(t, s, newMessage("task-idle", "hello"))
+	waitForState(t, s, "task-idle", model.TaskStateWorking)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := s.SubscribeToTaskUpdates(ctx, model.NewTaskQueryParams("task-idle"))
+	if err != nil {
+		t.Fatalf("SubscribeToTaskUpdates() error = %v", err)
+	}
+	cancel()
+
+	// The executor sends nothing more, only the context can end the subscription
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-stream:
+			if !ok {
+				if _, err := s.CancelTask(context.Background(), "task-idle"); err != nil {
+					t.Fatalf("CancelTask() error = %v", err)
+				}
+				<-canceled
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription still open after its context was canceled")
+		}
+	}
+}
+
+func TestSubscribeRejectsInvalidParams(t *testing.T) {
+	s := newServer(workUntilCanceled(make(chan struct{})))
+	params := model.NewTaskQueryParamsWithHistory("task-1", -1)
+	if _, err := s.SubscribeToTaskUpdates(context.Background(), params); !exception.HasCode(err, exception.InvalidParams) {
+		t.Errorf("SubscribeToTaskUpdates() error = %v, want InvalidParams", err)
+	}
+}
+
+func TestSubscribeAfterRunEndedRepliesWithStoredTask(t *testing.T) {
+	s := newServer(func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+		return server.NewTaskUpdaterForRequest(requestCtx, queue).Complete(nil)
+	})
+	if _, err := s.HandleMessage(context.Background(), newMessage("task-ended", "hello")); err != nil {
+		t.Fatalf("HandleMessage() error = %v", err)
+	}
+
+	resumed := model.NewTaskQueryParams("task-ended")
+	resumed.SetOffset(1)
+	for _, params := range []*model.TaskQueryParams{model.NewTaskQueryParams("task-ended"), resumed} {
+		stream, err := s.SubscribeToTaskUpdates(context.Background(), params)
+		if err != nil {
+			t.Fatalf("SubscribeToTaskUpdates() error = %v", err)
+		}
+		responses := collect(stream)
+		if len(responses) != 1 || lastState(responses) != model.TaskStateCompleted {
+			t.Errorf("SubscribeToTaskUpdates() streamed %#v, want only the completed task", responses)
+		}
+	}
+
+	if _, err := s.SubscribeToTaskUpdates(context.Background(), model.NewTaskQueryParams("task-unknown")); err == nil {
+		t.Error("SubscribeToTaskUpdates() of an unknown task succeeded, want an error")
+	}
+}
+
+func TestStreamSeesTheFinalStateTheTaskKeeps(t *testing.T) {
+	release := make(chan struct{})
+	completed := make(chan error, 1)
+	// The executor completes the task while the agent is asked to cancel it
+	s := newAgentServer(&testAgent{
+		execute: func(ctx context.Context, requestCtx *model.RequestContext, queue *server.EventQueue) error {
+			updater := server.NewTaskUpdaterForRequest(requestCtx, queue)
+			if err := updater.StartWork(nil); err != nil {
+				return err
+			}
+			<-release
+			completed <- updater.Complete(nil)
+			return nil
+		},
+		cancel: func(taskID string) {
+			close(release)
+			<-completed
+		},
+	})
+
+	stream, err := s.HandleMessageStream(context.Background(), newMessage("task-race", "hello"))
+	if err != nil {
+		t.Fatalf("HandleMessageStream() error = %v", err)
+	}
+	var responses []model.SendStreamingMessageResponse
+	for response := range stream {
+		responses = append(responses, unwrap(response))
+		if lastState(responses) == model.TaskStateWorking {
+			break
+		}
+	}
+
+	if _, err := s.CancelTask(context.Background(), "task-race"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	streamed := lastState(append(responses, collect(stream)...))
+	task, err := s.GetTask(context.Background(), "task-race")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if streamed != model.TaskStateCanceled || task.Status.State != model.TaskStateCanceled {
+		t.Errorf("stream ended in %s, task kept %s, want both canceled", streamed, task.Status.State)
+	}
+}
@@ -3,13 +3,16 @@ package impl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 
 	"github.com/a2ap/a2ago/pkg/service/server"
 
-	"github.com/a2ap/a2ago/internal/jsonrpc"
-	"github.com/a2ap/a2ago/internal/model"
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/jsonrpc"
+	"github.com/a2ap/a2ago/pkg/model"
 )
 
 // DefaultDispatcher implements the Dispatcher interface for handling JSON-RPC requests
@@ -25,12 +28,11 @@ func NewDefaultDispatcher(a2aServer server.A2AServer) *DefaultDispatcher {
 }
 
 // Dispatch handles synchronous JSON-RPC requests
-func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.JSONRPCResponse {
+func (d *DefaultDispatcher) Dispatch(ctx context.Context, request *jsonrpc.JSONRPCRequest) *jsonrpc.JSONRPCResponse {
 	response := &jsonrpc.JSONRPCResponse{
 		ID: request.ID,
 	}
 
-	ctx := context.Background()
 	switch request.Method {
 	case "message/send":
 		var params model.MessageSendParams
@@ -53,17 +55,13 @@ func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.J
 		}
 		messageResponse, err := d.a2aServer.HandleMessage(ctx, &params)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			return response
 		}
 		response.Result = messageResponse
 
 	case "tasks/get":
-		var params model.TaskIdParams
+		var params model.TaskQueryParams
 		paramsBytes, err := json.Marshal(request.Params)
 		if err != nil {
 			response.Error = &jsonrpc.JSONRPCError{
@@ -81,13 +79,9 @@ func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.J
 			}
 			return response
 		}
-		task, err := d.a2aServer.GetTask(ctx, params.ID)
+		task, err := d.a2aServer.QueryTask(ctx, &params)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			return response
 		}
 		response.Result = task
@@ -113,11 +107,7 @@ func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.J
 		}
 		task, err := d.a2aServer.CancelTask(ctx, params.ID)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			return response
 		}
 		response.Result = task
@@ -143,11 +133,7 @@ func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.J
 		}
 		setResult, err := d.a2aServer.SetTaskPushNotification(ctx, config.GetTaskID(), &config)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			return response
 		}
 		response.Result = setResult
@@ -173,11 +159,7 @@ func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.J
 		}
 		getConfigResult, err := d.a2aServer.GetTaskPushNotification(ctx, params.ID)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			return response
 		}
 		response.Result = getConfigResult
@@ -195,12 +177,11 @@ func (d *DefaultDispatcher) Dispatch(request *jsonrpc.JSONRPCRequest) *jsonrpc.J
 }
 
 // DispatchStream handles streaming JSON-RPC requests
-func (d *DefaultDispatcher) DispatchStream(request *jsonrpc.JSONRPCRequest) (<-chan *jsonrpc.JSONRPCResponse, error) {
+func (d *DefaultDispatcher) DispatchStream(ctx context.Context, request *jsonrpc.JSONRPCRequest) (<-chan *jsonrpc.JSONRPCResponse, error) {
 	response := &jsonrpc.JSONRPCResponse{
 		ID: request.ID,
 	}
 
-	ctx := context.Background()
 	switch request.Method {
 	case "message/stream":
 		var params model.MessageSendParams
@@ -229,30 +210,16 @@ func (d *DefaultDispatcher) DispatchStream(request *jsonrpc.JSONRPCRequest) (<-c
 		}
 		modelResponses, err := d.a2aServer.HandleMessageStream(ctx, &params)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			ch := make(chan *jsonrpc.JSONRPCResponse, 1)
 			ch <- response
 			close(ch)
 			return ch, nil
 		}
-		responses := make(chan *jsonrpc.JSONRPCResponse)
-		go func() {
-			for resp := range modelResponses {
-				responses <- &jsonrpc.JSONRPCResponse{
-					ID:     request.ID,
-					Result: resp,
-				}
-			}
-			close(responses)
-		}()
-		return responses, nil
+		return streamResponses(request.ID, modelResponses), nil
 
 	case "tasks/resubscribe":
-		var params model.TaskIdParams
+		var params model.TaskQueryParams
 		paramsBytes, err := json.Marshal(request.Params)
 		if err != nil {
 			response.Error = &jsonrpc.JSONRPCError{
@@ -276,29 +243,15 @@ func (d *DefaultDispatcher) DispatchStream(request *jsonrpc.JSONRPCRequest) (<-c
 			close(ch)
 			return ch, nil
 		}
-		modelResponses, err := d.a2aServer.SubscribeToTaskUpdates(ctx, params.ID)
+		modelResponses, err := d.a2aServer.SubscribeToTaskUpdates(ctx, &params)
 		if err != nil {
-			response.Error = &jsonrpc.JSONRPCError{
-				Code:    jsonrpc.InternalError,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}
+			response.Error = toJSONRPCError(err)
 			ch := make(chan *jsonrpc.JSONRPCResponse, 1)
 			ch <- response
 			close(ch)
 			return ch, nil
 		}
-		responses := make(chan *jsonrpc.JSONRPCResponse)
-		go func() {
-			for resp := range modelResponses {
-				responses <- &jsonrpc.JSONRPCResponse{
-					ID:     request.ID,
-					Result: resp,
-				}
-			}
-			close(responses)
-		}()
-		return responses, nil
+		return streamResponses(request.ID, modelResponses), nil
 
 	default:
 		log.Printf("Unsupported method: %s", request.Method)
@@ -313,3 +266,46 @@ func (d *DefaultDispatcher) DispatchStream(request *jsonrpc.JSONRPCRequest) (<-c
 		return ch, nil
 	}
 }
+
+// streamResponses wraps streaming model responses into JSON-RPC responses,
+// carrying the sequence id of sequenced responses as the event id and error responses as JSON-RPC errors
+func streamResponses(id string, modelResponses <-chan *model.SendStreamingMessageResponse) <-chan *jsonrpc.JSONRPCResponse {
+	responses := make(chan *jsonrpc.JSONRPCResponse)
+	go func() {
+		for resp := range modelResponses {
+			response := &jsonrpc.JSONRPCResponse{
+				ID:     id,
+				Result: resp,
+			}
+			switch r := (*resp).(type) {
+			case *server.SequencedResponse:
+				response.Result = r.Response
+				response.EventID = strconv.FormatUint(r.Seq, 10)
+			case *server.ErrorResponse:
+				response.Result = nil
+				response.Error = toJSONRPCError(r.Err)
+			}
+			responses <- response
+		}
+		close(responses)
+	}()
+	return responses
+}
+
+// toJSONRPCError converts an error returned by the A2A server into a JSON-RPC error,
+// keeping the code of A2A errors and reporting anything else as an internal error
+func toJSONRPCError(err error) *jsonrpc.JSONRPCError {
+	var a2aErr *exception.A2AError
+	if errors.As(err, &a2aErr) && a2aErr.Code != 0 {
+		return &jsonrpc.JSONRPCError{
+			Code:    a2aErr.Code,
+			Message: a2aErr.Message,
+			Data:    a2aErr.Data,
+		}
+	}
+	return &jsonrpc.JSONRPCError{
+		Code:    jsonrpc.InternalError,
+		Message: "Internal error",
+		Data:    err.Error(),
+	}
+}
@@ -0,0 +1,465 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/jsonrpc"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+const (
+	// DefaultAgentCardPath is the well-known path serving the public agent card
+	DefaultAgentCardPath = "/.well-known/agent.json"
+
+	// DefaultJSONRPCPath is the path accepting JSON-RPC requests
+	DefaultJSONRPCPath = "/a2a/server"
+
+	// DefaultExtendedCardPath is the path serving the authenticated extended agent card
+	DefaultExtendedCardPath = "/a2a/agent/authenticatedExtendedCard"
+
+	// DefaultSSEEventName is the SSE event name used for streaming responses
+	DefaultSSEEventName = "task-update"
+
+	// ExtensionsHeader is the request header listing the URIs of the protocol extensions requested by the client
+	ExtensionsHeader = "X-A2A-Extensions"
+
+	// DeadlineHeader is the request header carrying the RFC 3339 time by which the client needs the task finished
+	DeadlineHeader = "X-A2A-Deadline"
+)
+
+// streamingMethods are the JSON-RPC methods answered with a Server-Sent Events stream
+var streamingMethods = map[string]bool{
+	"message/stream":    true,
+	"tasks/resubscribe": true,
+}
+
+// CorsConfig configures the CORS headers written by HttpHandler
+type CorsConfig struct {
+	// AllowOrigins are the allowed origins, "*" allows any origin without credentials
+	AllowOrigins []string
+	// AllowMethods are the allowed HTTP methods
+	AllowMethods []string
+	// AllowHeaders are the allowed request headers, "*" allows any header
+	AllowHeaders []string
+	// ExposeHeaders are the response headers exposed to the browser
+	ExposeHeaders []string
+	// AllowCredentials indicates whether credentials are allowed.
+	// They are only allowed for the origins listed in AllowOrigins, never for an origin allowed by "*".
+	AllowCredentials bool
+	// MaxAge is how long the preflight response may be cached
+	MaxAge time.Duration
+}
+
+// DefaultCorsConfig returns a permissive CORS configuration allowing any origin without credentials
+func DefaultCorsConfig() *CorsConfig {
+	return &CorsConfig{
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"*"},
+		ExposeHeaders: []string{"Content-Length"},
+		MaxAge:        12 * time.Hour,
+	}
+}
+
+// ForbiddenError is returned by an ExtendedCardAuthenticator to reject a request with 403 Forbidden
+// instead of 401 Unauthorized, for example when the presented credentials are invalid
+type ForbiddenError struct {
+	// Message is the error message
+	Message string
+}
+
+// NewForbiddenError creates a new ForbiddenError
+func NewForbiddenError(message string) *ForbiddenError {
+	return &ForbiddenError{Message: message}
+}
+
+// Error returns the error message
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// HttpHandlerConfig configures the endpoints served by HttpHandler
+type HttpHandlerConfig struct {
+	// AgentCardPath is the path serving the public agent card
+	AgentCardPath string
+	// JSONRPCPath is the path accepting JSON-RPC POST requests
+	JSONRPCPath string
+	// ExtendedCardPath is the path serving the authenticated extended card, empty disables it
+	ExtendedCardPath string
+	// SSEEventName is the event name written for every streamed response
+	SSEEventName string
+	// Cors configures CORS headers, nil disables CORS handling
+	Cors *CorsConfig
+	// ExtendedCardAuthenticator authenticates requests for the extended card, nil leaves the card unserved (404 Not Found).
+	// A non-nil error rejects the request with 401 Unauthorized, or 403 Forbidden for a *ForbiddenError.
+	ExtendedCardAuthenticator func(r *http.Request) error
+	// Authenticator authenticates JSON-RPC requests, the returned user is handed to the agent executor.
+	// A non-nil error rejects the request with 401 Unauthorized; nil leaves every request anonymous.
+	Authenticator func(r *http.Request) (*model.User, error)
+}
+
+// DefaultHttpHandlerConfig returns the endpoint layout used by the A2A examples
+func DefaultHttpHandlerConfig() *HttpHandlerConfig {
+	return &HttpHandlerConfig{
+		AgentCardPath:    DefaultAgentCardPath,
+		JSONRPCPath:      DefaultJSONRPCPath,
+		ExtendedCardPath: DefaultExtendedCardPath,
+		SSEEventName:     DefaultSSEEventName,
+	}
+}
+
+// HttpHandler is a net/http handler serving a complete A2A endpoint:
+// the agent card, JSON-RPC requests and SSE streaming responses
+type HttpHandler struct {
+	a2aServer  server.A2AServer
+	dispatcher server.Dispatcher
+	config     *HttpHandlerConfig
+}
+
+// NewHttpHandler creates a new HttpHandler. A nil config uses DefaultHttpHandlerConfig.
+func NewHttpHandler(a2aServer server.A2AServer, dispatcher server.Dispatcher, config *HttpHandlerConfig) *HttpHandler {
+	defaults := DefaultHttpHandlerConfig()
+	if config == nil {
+		config = defaults
+	} else {
+		c := *config
+		if c.AgentCardPath == "" {
+			c.AgentCardPath = defaults.AgentCardPath
+		}
+		if c.JSONRPCPath == "" {
+			c.JSONRPCPath = defaults.JSONRPCPath
+		}
+		if c.SSEEventName == "" {
+			c.SSEEventName = defaults.SSEEventName
+		}
+		config = &c
+	}
+	return &HttpHandler{
+		a2aServer:  a2aServer,
+		dispatcher: dispatcher,
+		config:     config,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (h *HttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.serveCors(w, r) {
+		return
+	}
+
+	switch r.URL.Path {
+	case h.config.AgentCardPath:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		writeJSON(w, http.StatusOK, h.a2aServer.GetSelfAgentCard())
+
+	case h.config.ExtendedCardPath:
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		h.serveExtendedCard(w, r)
+
+	case h.config.JSONRPCPath:
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, http.MethodPost)
+			return
+		}
+		h.serveJSONRPC(w, r)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveExtendedCard serves the authenticated extended agent card
+func (h *HttpHandler) serveExtendedCard(w http.ResponseWriter, r *http.Request) {
+	// The extended card is never served without an authenticator to protect it
+	if h.config.ExtendedCardAuthenticator == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := h.config.ExtendedCardAuthenticator(r); err != nil {
+		status := http.StatusUnauthorized
+		var forbidden *ForbiddenError
+		if errors.As(err, &forbidden) {
+			status = http.StatusForbidden
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	extendedCard, err := h.a2aServer.GetAuthenticatedExtendedCard(r.Context())
+	if err != nil {
+		log.Printf("Error getting extended agent card: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to get extended agent card"})
+		return
+	}
+	writeJSON(w, http.StatusOK, extendedCard)
+}
+
+// serveJSONRPC decodes a JSON-RPC request and answers it with JSON or an SSE stream
+func (h *HttpHandler) serveJSONRPC(w http.ResponseWriter, r *http.Request) {
+	ctx, err := h.callerContext(r)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var request jsonrpc.JSONRPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusOK, &jsonrpc.JSONRPCResponse{
+			Error: &jsonrpc.JSONRPCError{
+				Code:    jsonrpc.ParseError,
+				Message: "Parse error",
+				Data:    err.Error(),
+			},
+		})
+		return
+	}
+
+	if request.Method == "tasks/resubscribe" {
+		applyLastEventID(&request, r.Header.Get("Last-Event-ID"))
+	}
+
+	if !streamingMethods[request.Method] {
+		writeJSON(w, http.StatusOK, h.dispatcher.Dispatch(ctx, &request))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusOK, &jsonrpc.JSONRPCResponse{
+			ID: request.ID,
+			Error: &jsonrpc.JSONRPCError{
+				Code:    jsonrpc.InternalError,
+				Message: "Internal error",
+				Data:    "streaming is not supported by the response writer",
+			},
+		})
+		return
+	}
+
+	responses, err := h.dispatcher.DispatchStream(ctx, &request)
+	if err != nil {
+		writeJSON(w, http.StatusOK, &jsonrpc.JSONRPCResponse{
+			ID: request.ID,
+			Error: &jsonrpc.JSONRPCError{
+				Code:    jsonrpc.InternalError,
+				Message: "Internal error",
+				Data:    err.Error(),
+			},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			go drain(responses)
+			return
+		case response, ok := <-responses:
+			if !ok {
+				return
+			}
+			if err := h.writeEvent(w, response); err != nil {
+				log.Printf("Error writing SSE event for request %s: %v", request.ID, err)
+				go drain(responses)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// callerContext returns the request context carrying the authenticated user, the requested extensions
+// and the deadline supplied by the client
+func (h *HttpHandler) callerContext(r *http.Request) (context.Context, error) {
+	ctx := r.Context()
+	if h.config.Authenticator != nil {
+		user, err := h.config.Authenticator(r)
+		if err != nil {
+			return nil, err
+		}
+		ctx = server.WithUser(ctx, user)
+	}
+
+	var extensions []string
+	for _, value := range r.Header.Values(ExtensionsHeader) {
+		for _, uri := range strings.Split(value, ",") {
+			if uri = strings.TrimSpace(uri); uri != "" {
+				extensions = append(extensions, uri)
+			}
+		}
+	}
+	if len(extensions) > 0 {
+		ctx = server.WithExtensions(ctx, extensions)
+	}
+
+	if value := r.Header.Get(DeadlineHeader); value != "" {
+		deadline, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			log.Printf("Ignoring invalid %s %q", DeadlineHeader, value)
+		} else {
+			ctx = server.WithClientDeadline(ctx, deadline)
+		}
+	}
+	return ctx, nil
+}
+
+// writeEvent writes a single SSE frame carrying a JSON-RPC response
+func (h *HttpHandler) writeEvent(w http.ResponseWriter, response *jsonrpc.JSONRPCResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	if response.EventID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", response.EventID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", h.config.SSEEventName, data)
+	return err
+}
+
+// applyLastEventID resumes a resubscription from the SSE Last-Event-ID header
+// unless the request params already carry an offset
+func applyLastEventID(request *jsonrpc.JSONRPCRequest, lastEventID string) {
+	if lastEventID == "" {
+		return
+	}
+	offset, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		log.Printf("Ignoring invalid Last-Event-ID %q for request %s", lastEventID, request.ID)
+		return
+	}
+	params, ok := request.Params.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, exists := params["offset"]; !exists {
+		params["offset"] = offset
+	}
+}
+
+// WithCors wraps next, typically another route of the same server, so it answers with the CORS headers
+// of the handler, preflight requests included. Without CORS configuration next is returned as is.
+func (h *HttpHandler) WithCors(next http.Handler) http.Handler {
+	if h.config.Cors == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.serveCors(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveCors writes the configured CORS headers and answers preflight requests.
+// It returns true when the request has been answered.
+func (h *HttpHandler) serveCors(w http.ResponseWriter, r *http.Request) bool {
+	if h.config.Cors == nil {
+		return false
+	}
+	h.writeCorsHeaders(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
+
+// writeCorsHeaders writes the configured CORS headers for the request origin.
+// A listed origin is reflected, with credentials when allowed; any other origin allowed by "*" gets "*".
+func (h *HttpHandler) writeCorsHeaders(w http.ResponseWriter, r *http.Request) {
+	cors := h.config.Cors
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+
+	listed, wildcard := false, false
+	for _, o := range cors.AllowOrigins {
+		switch o {
+		case origin:
+			listed = true
+		case "*":
+			wildcard = true
+		}
+	}
+	if !listed && !wildcard {
+		return
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	if listed {
+		header.Set("Access-Control-Allow-Origin", origin)
+		if cors.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+	} else {
+		header.Set("Access-Control-Allow-Origin", "*")
+	}
+	if len(cors.ExposeHeaders) > 0 {
+		header.Set("Access-Control-Expose-Headers", strings.Join(cors.ExposeHeaders, ", "))
+	}
+
+	if r.Method != http.MethodOptions {
+		return
+	}
+	if len(cors.AllowMethods) > 0 {
+		header.Set("Access-Control-Allow-Methods", strings.Join(cors.AllowMethods, ", "))
+	}
+	allowHeaders := strings.Join(cors.AllowHeaders, ", ")
+	if allowHeaders == "*" {
+		// Echo the requested headers since "*" is not honored with credentials
+		allowHeaders = r.Header.Get("Access-Control-Request-Headers")
+	}
+	if allowHeaders != "" {
+		header.Set("Access-Control-Allow-Headers", allowHeaders)
+	}
+	if cors.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(cors.MaxAge.Seconds())))
+	}
+}
+
+// drain consumes the remaining responses so the producing goroutine can exit
+func drain(responses <-chan *jsonrpc.JSONRPCResponse) {
+	for range responses {
+	}
+}
+
+// writeJSON writes a JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error writing JSON response: %v", err)
+	}
+}
+
+// writeMethodNotAllowed rejects a request made with an unsupported HTTP method
+func writeMethodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+}
@@ -0,0 +1,115 @@
+package impl_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server/impl"
+)
+
+// newTestHandler creates an HttpHandler without agent executor, serving the agent card and the extended card
+func newTestHandler(config *impl.HttpHandlerConfig) *impl.HttpHandler {
+	a2aServer := impl.NewDefaultA2AServer(impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore()),
+		impl.NewInMemoryQueueManager(), nil, &model.AgentCard{Name: "test"})
+	return impl.NewHttpHandler(a2aServer, impl.NewDefaultDispatcher(a2aServer), config)
+}
+
+func TestCorsHeaders(t *testing.T) {
+	withCredentials := impl.DefaultCorsConfig()
+	withCredentials.AllowOrigins = []string{"https://app.example.com", "*"}
+	withCredentials.AllowCredentials = true
+
+	tests := []struct {
+		name            string
+		cors            *impl.CorsConfig
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"default allows any origin without credentials", impl.DefaultCorsConfig(), "https://evil.example.com", "*", ""},
+		{"listed origin is reflected with credentials", withCredentials, "https://app.example.com", "https://app.example.com", "true"},
+		{"wildcard origin gets no credentials", withCredentials, "https://evil.example.com", "*", ""},
+		{"unlisted origin gets no headers", &impl.CorsConfig{AllowOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, "https://evil.example.com", "", ""},
+		{"request without origin gets no headers", impl.DefaultCorsConfig(), "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := impl.DefaultHttpHandlerConfig()
+			config.Cors = tt.cors
+			handler := newTestHandler(config)
+
+			request := httptest.NewRequest(http.MethodGet, impl.DefaultAgentCardPath, nil)
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := recorder.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+		})
+	}
+}
+
+func TestWithCorsAnswersPreflight(t *testing.T) {
+	config := impl.DefaultHttpHandlerConfig()
+	config.Cors = impl.DefaultCorsConfig()
+	called := false
+	wrapped := newTestHandler(config).WithCors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	request := httptest.NewRequest(http.MethodOptions, "/a2a/tasks", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	recorder := httptest.NewRecorder()
+	wrapped.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusNoContent)
+	}
+	if recorder.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("preflight is missing the CORS headers: %v", recorder.Header())
+	}
+	if called {
+		t.Error("preflight request reached the wrapped handler")
+	}
+}
+
+func TestExtendedCardAuthenticationStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		noAuth     bool
+		wantStatus int
+	}{
+		{"authenticated", nil, false, http.StatusOK},
+		{"missing credentials", errors.New("API key required"), false, http.StatusUnauthorized},
+		{"invalid credentials", impl.NewForbiddenError("Invalid API key"), false, http.StatusForbidden},
+		{"no authenticator", nil, true, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := impl.DefaultHttpHandlerConfig()
+			if !tt.noAuth {
+				config.ExtendedCardAuthenticator = func(r *http.Request) error {
+					return tt.err
+				}
+			}
+			handler := newTestHandler(config)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, impl.DefaultExtendedCardPath, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
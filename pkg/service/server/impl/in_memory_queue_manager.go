@@ -10,23 +10,26 @@ import (
 
 // InMemoryQueueManager is an in-memory implementation of the QueueManager interface
 type InMemoryQueueManager struct {
-	queues map[string]*server.EventQueue
-	mu     sync.RWMutex
+	queues      map[string]*server.EventQueue
+	mu          sync.RWMutex
+	defaultOpts []server.QueueOption
 }
 
-// NewInMemoryQueueManager creates a new InMemoryQueueManager
-func NewInMemoryQueueManager() server.QueueManager {
+// NewInMemoryQueueManager creates a new InMemoryQueueManager, opts are the default options of every queue it creates
+func NewInMemoryQueueManager(opts ...server.QueueOption) server.QueueManager {
 	return &InMemoryQueueManager{
-		queues: make(map[string]*server.EventQueue),
+		queues:      make(map[string]*server.EventQueue),
+		defaultOpts: opts,
 	}
 }
 
-// Create creates a new queue for a task
-func (m *InMemoryQueueManager) Create(ctx context.Context, taskID string) (*server.EventQueue, error) {
+// Create creates a new queue for a task, opts override the default queue options
+func (m *InMemoryQueueManager) Create(ctx context.Context, taskID string, opts ...server.QueueOption) (*server.EventQueue, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	queue := server.NewEventQueue()
+	queueOpts := append(append([]server.QueueOption(nil), m.defaultOpts...), opts...)
+	queue := server.NewEventQueue(queueOpts...)
 	m.queues[taskID] = queue
 	//log.Printf("[QueueManager] Create: taskID=%s, queue=%p\n%s", taskID, queue, debug.Stack())
 	return queue, nil
@@ -46,7 +49,7 @@ func (m *InMemoryQueueManager) Get(ctx context.Context, taskID string) (*server.
 }
 
 // Tap taps into an existing task's queue to create a child queue
-func (m *InMemoryQueueManager) Tap(ctx context.Context, taskID string) (*server.EventQueue, error) {
+func (m *InMemoryQueueManager) Tap(ctx context.Context, taskID string, opts ...server.QueueOption) (*server.EventQueue, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -55,24 +58,31 @@ func (m *InMemoryQueueManager) Tap(ctx context.Context, taskID string) (*server.
 	if !exists {
 		return nil, fmt.Errorf("queue not found for task ID: %s", taskID)
 	}
-	return queue.Tap()
+	return queue.Tap(opts...)
 }
 
-// Remove removes a queue for a task
-func (m *InMemoryQueueManager) Remove(ctx context.Context, taskID string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// TapFrom taps into an existing task's queue, replaying the retained events after afterSeq first
+func (m *InMemoryQueueManager) TapFrom(ctx context.Context, taskID string, afterSeq uint64, opts ...server.QueueOption) (*server.EventQueue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
 	queue, exists := m.queues[taskID]
-	//log.Printf("[QueueManager] Remove: taskID=%s, exists=%v, queue=%p\n%s", taskID, exists, queue, debug.Stack())
 	if !exists {
-		return nil
+		return nil, fmt.Errorf("queue not found for task ID: %s", taskID)
 	}
+	return queue.TapFrom(afterSeq, opts...)
+}
+
+// Remove closes queue and removes it as the queue of the task, unless another queue replaced it
+func (m *InMemoryQueueManager) Remove(ctx context.Context, taskID string, queue *server.EventQueue) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	if err := queue.Close(); err != nil {
 		return err
 	}
-
-	delete(m.queues, taskID)
+	if m.queues[taskID] == queue {
+		delete(m.queues, taskID)
+	}
 	return nil
 }
@@ -0,0 +1,488 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/a2ap/a2ago/internal/util"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+// InMemoryTaskManager is an in-memory implementation of the TaskManager interface.
+// Stored tasks are never modified in place: every update is applied to a copy of the latest stored task,
+// which then replaces it, and callers only get copies. A task handed out can be read without locking.
+type InMemoryTaskManager struct {
+	taskStore           server.TaskStore
+	notificationConfigs map[string]*model.TaskPushNotificationConfig
+	contextTaskIDs      map[string]map[string]bool
+	mu                  sync.RWMutex
+}
+
+// NewInMemoryTaskManager creates a new InMemoryTaskManager
+func NewInMemoryTaskManager(taskStore server.TaskStore) server.TaskManager {
+	return &InMemoryTaskManager{
+		taskStore:           taskStore,
+		notificationConfigs: make(map[string]*model.TaskPushNotificationConfig),
+		contextTaskIDs:      make(map[string]map[string]bool),
+	}
+}
+
+// LoadOrCreateContext loads or creates a new task context
+func (m *InMemoryTaskManager) LoadOrCreateContext(ctx context.Context, params *model.MessageSendParams) (*model.RequestContext, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	taskID := params.Message.TaskID
+	if taskID == "" {
+		taskID = util.GenerateUUID()
+	}
+
+	contextID := params.Message.ContextID
+	if contextID == "" {
+		contextID = util.GenerateUUID()
+	}
+
+	// Resolve the referenced tasks first, so a message with an unknown reference leaves no task behind
+	referenceTasks, err := m.loadReferenceTasks(ctx, params.Message.ReferenceTaskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load or create task
+	task, err := m.taskStore.Load(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task: %w", err)
+	}
+
+	var message *model.Message
+	if task == nil {
+		// Create new task
+		task = model.NewTask(taskID)
+		task.ContextID = contextID
+		task.RecordStatus(model.NewTaskStatus(model.TaskStateSubmitted), "")
+		task.Metadata = params.Metadata
+		task.Artifacts = make([]*model.Artifact, 0)
+		message = stampMessage(params.Message, taskID, contextID)
+		task.History = []*model.Message{message}
+
+		if err := m.taskStore.Save(ctx, task); err != nil {
+			return nil, fmt.Errorf("failed to save new task: %w", err)
+		}
+	} else if task.Status != nil && task.Status.State.IsFinal() {
+		// A finished task is immutable, the client has to start a new task
+		return nil, exception.NewTaskTerminalError(taskID, task.Status.State)
+	} else {
+		// A follow-up message continues the conversation of the task
+		task = task.Clone()
+		message = stampMessage(params.Message, taskID, task.ContextID)
+		task.History = append(task.History, message)
+
+		// Resume a task that is waiting to start or waiting for the client
+		if reason, ok := resumeReason(task.Status); ok {
+			task, err = m.applyStatusUpdate(task, &model.TaskStatusUpdateEvent{
+				TaskID:    task.ID,
+				ContextID: task.ContextID,
+				Kind:      model.KindStatusUpdate,
+				Status:    model.NewTaskStatus(model.TaskStateWorking),
+				Metadata:  map[string]interface{}{model.StatusReasonKey: reason},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to update task status: %w", err)
+			}
+		}
+		if err := m.taskStore.Save(ctx, task); err != nil {
+			return nil, fmt.Errorf("failed to save task: %w", err)
+		}
+	}
+
+	// Create request context
+	contextID = message.ContextID
+	requestCtx := model.NewRequestContext(taskID, contextID, task.Clone())
+	requestCtx.Message = message
+	requestCtx.ReferenceTasks = referenceTasks
+	requestCtx.Configuration = params.Configuration
+	requestCtx.Metadata = params.Metadata
+	requestCtx.Extensions = server.ExtensionsFromContext(ctx)
+	requestCtx.User = server.UserFromContext(ctx)
+
+	// Update context-task mapping
+	if _, exists := m.contextTaskIDs[contextID]; !exists {
+		m.contextTaskIDs[contextID] = make(map[string]bool)
+	}
+	m.contextTaskIDs[contextID][taskID] = true
+
+	// Add related tasks
+	relatedTasks := make([]*model.Task, 0)
+	for relatedTaskID := range m.contextTaskIDs[contextID] {
+		if relatedTaskID != taskID {
+			if relatedTask, err := m.taskStore.Load(ctx, relatedTaskID); err == nil && relatedTask != nil {
+				relatedTasks = append(relatedTasks, relatedTask.Clone())
+			}
+		}
+	}
+	requestCtx.RelatedTasks = relatedTasks
+
+	return requestCtx, nil
+}
+
+// loadReferenceTasks loads the tasks a message refers to, failing with exception.InvalidParams on an unknown task
+func (m *InMemoryTaskManager) loadReferenceTasks(ctx context.Context, taskIDs []string) ([]*model.Task, error) {
+	referenceTasks := make([]*model.Task, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		task, err := m.taskStore.Load(ctx, taskID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load reference task: %w", err)
+		}
+		if task == nil {
+			return nil, exception.NewInvalidParamsError(fmt.Errorf("reference task %s not found", taskID))
+		}
+		referenceTasks = append(referenceTasks, task.Clone())
+	}
+	return referenceTasks, nil
+}
+
+// GetTask returns a copy of a task by its ID
+func (m *InMemoryTaskManager) GetTask(ctx context.Context, taskID string) (*model.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	task, err := m.taskStore.Load(ctx, taskID)
+	if err != nil || task == nil {
+		return task, err
+	}
+	return task.Clone(), nil
+}
+
+// latest returns a copy of the stored version of task to apply an update to,
+// or of task itself when it is not stored; m.mu must be held
+func (m *InMemoryTaskManager) latest(ctx context.Context, task *model.Task) (*model.Task, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+	stored, err := m.taskStore.Load(ctx, task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task: %w", err)
+	}
+	if stored != nil {
+		task = stored
+	}
+	return task.Clone(), nil
+}
+
+// QueryTask returns a copy of a task with its history trimmed to the requested length
+func (m *InMemoryTaskManager) QueryTask(ctx context.Context, params *model.TaskQueryParams) (*model.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	task, err := m.taskStore.Load(ctx, params.TaskID)
+	if err != nil || task == nil {
+		return task, err
+	}
+	queried := task.Clone()
+	queried.History = task.RecentHistory(params.HistoryLength)
+	return queried, nil
+}
+
+// ApplyTaskUpdate applies a list of task updates
+func (m *InMemoryTaskManager) ApplyTaskUpdate(ctx context.Context, task *model.Task, updates []model.TaskUpdate) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, err := m.latest(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	for _, update := range updates {
+		switch u := update.(type) {
+		case *model.TaskStatusUpdateEvent:
+			task, err = m.applyStatusUpdate(task, u)
+		case *model.TaskStatus:
+			task, err = m.applyStatusUpdate(task, &model.TaskStatusUpdateEvent{
+				TaskID:    task.ID,
+				ContextID: task.ContextID,
+				Kind:      model.KindStatusUpdate,
+				Status:    u,
+				Final:     u.State.IsFinal(),
+			})
+		case *model.TaskArtifactUpdateEvent:
+			task, err = m.applyArtifactUpdate(task, u)
+		case *model.Message:
+			task.History = append(task.History, u)
+		default:
+			return nil, fmt.Errorf("unsupported task update type: %T", update)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.taskStore.Save(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to save task: %w", err)
+	}
+
+	return task, nil
+}
+
+// ApplyTaskUpdateSingle applies a single task update
+func (m *InMemoryTaskManager) ApplyTaskUpdateSingle(ctx context.Context, task *model.Task, update model.TaskUpdate) (*model.Task, error) {
+	return m.ApplyTaskUpdate(ctx, task, []model.TaskUpdate{update})
+}
+
+// ApplyStatusUpdate applies a status update to a task
+func (m *InMemoryTaskManager) ApplyStatusUpdate(ctx context.Context, task *model.Task, event *model.TaskStatusUpdateEvent) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, err := m.latest(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	updatedTask, err := m.applyStatusUpdate(task, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.taskStore.Save(ctx, updatedTask); err != nil {
+		return nil, fmt.Errorf("failed to save task: %w", err)
+	}
+
+	return updatedTask, nil
+}
+
+// ApplyArtifactUpdate applies an artifact update to a task
+func (m *InMemoryTaskManager) ApplyArtifactUpdate(ctx context.Context, task *model.Task, event *model.TaskArtifactUpdateEvent) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, err := m.latest(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	updatedTask, err := m.applyArtifactUpdate(task, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.taskStore.Save(ctx, updatedTask); err != nil {
+		return nil, fmt.Errorf("failed to save task: %w", err)
+	}
+
+	return updatedTask, nil
+}
+
+// CancelTask atomically persists the canceled status of a task.
+// Cancellation and status updates are serialized, so whichever reaches a final state first wins.
+func (m *InMemoryTaskManager) CancelTask(ctx context.Context, taskID string) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, err := m.taskStore.Load(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task: %w", err)
+	}
+	if task == nil {
+		return nil, exception.NewA2AErrorWithAll(fmt.Sprintf("task with ID %s not found", taskID), exception.TaskNotFound, nil, taskID)
+	}
+	if task.Status != nil && task.Status.State.IsFinal() {
+		return nil, exception.NewTaskNotCancelableError(taskID, task.Status.State.String())
+	}
+
+	updatedTask, err := m.applyStatusUpdate(task.Clone(), &model.TaskStatusUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Kind:      model.KindStatusUpdate,
+		Status:    model.NewTaskStatus(model.TaskStateCanceled),
+		Final:     true,
+		Metadata:  map[string]interface{}{model.StatusReasonKey: "canceled by client request"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.taskStore.Save(ctx, updatedTask); err != nil {
+		return nil, fmt.Errorf("failed to save task: %w", err)
+	}
+
+	return updatedTask, nil
+}
+
+// RegisterTaskNotification registers a task notification config
+func (m *InMemoryTaskManager) RegisterTaskNotification(ctx context.Context, config *model.TaskPushNotificationConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.notificationConfigs[config.TaskID] = config
+	return nil
+}
+
+// GetTaskNotification gets a task notification config
+func (m *InMemoryTaskManager) GetTaskNotification(ctx context.Context, taskID string) (*model.TaskPushNotificationConfig, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.notificationConfigs[taskID]
+	if !exists {
+		return nil, nil
+	}
+
+	return config, nil
+}
+
+// applyStatusUpdate applies a status update to a task, which has to be a copy not yet stored
+func (m *InMemoryTaskManager) applyStatusUpdate(task *model.Task, event *model.TaskStatusUpdateEvent) (*model.Task, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+
+	if event == nil || event.Status == nil {
+		return nil, fmt.Errorf("invalid status update event")
+	}
+
+	// Enforce the task lifecycle, repeating the final state of a finished task is a no-op
+	if task.Status != nil {
+		from, to := task.Status.State, event.Status.State
+		if from.IsFinal() && from == to {
+			return task, nil
+		}
+		if !from.CanTransitionTo(to) {
+			return nil, exception.NewInvalidTransitionError(task.ID, from, to)
+		}
+	}
+
+	task.RecordStatus(event.Status, statusReason(event))
+
+	// Check if the status update includes an agent message and add it to history
+	if event.Status.Message != nil && event.Status.Message.Role == model.RoleAgent {
+		if task.History == nil {
+			task.History = make([]*model.Message, 0)
+		}
+		task.History = append(task.History, event.Status.Message)
+	}
+
+	return task, nil
+}
+
+// stampMessage returns a copy of a client message carrying the task and context IDs it was assigned to,
+// the IDs the client sent are kept
+func stampMessage(message *model.Message, taskID, contextID string) *model.Message {
+	stamped := *message
+	if stamped.TaskID == "" {
+		stamped.TaskID = taskID
+	}
+	if stamped.ContextID == "" {
+		stamped.ContextID = contextID
+	}
+	return &stamped
+}
+
+// resumeReason reports whether a follow-up message moves a task in the given status back to working, and why
+func resumeReason(status *model.TaskStatus) (string, bool) {
+	if status == nil {
+		return "", false
+	}
+	switch status.State {
+	case model.TaskStateSubmitted:
+		return "", true
+	case model.TaskStateInputRequired, model.TaskStateAuthRequired:
+		return "input received", true
+	default:
+		return "", false
+	}
+}
+
+// statusReason returns why a status update happened, from the "reason" metadata or the status error
+func statusReason(event *model.TaskStatusUpdateEvent) string {
+	for _, metadata := range []map[string]interface{}{event.Status.Metadata, event.Metadata} {
+		if reason, ok := metadata[model.StatusReasonKey].(string); ok {
+			return reason
+		}
+	}
+	return event.Status.Error
+}
+
+// applyArtifactUpdate applies an artifact update to a task, which has to be a copy not yet stored
+func (m *InMemoryTaskManager) applyArtifactUpdate(task *model.Task, event *model.TaskArtifactUpdateEvent) (*model.Task, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is nil")
+	}
+
+	if event == nil || event.Artifact == nil {
+		return nil, fmt.Errorf("invalid artifact update event")
+	}
+
+	if task.Status != nil && task.Status.State.IsFinal() {
+		return nil, exception.NewTaskTerminalError(task.ID, task.Status.State)
+	}
+
+	artifactID := event.Artifact.ArtifactID
+	index := -1
+	for i, artifact := range task.Artifacts {
+		if artifact.ArtifactID == artifactID {
+			index = i
+			break
+		}
+	}
+
+	// Stored artifacts are never modified in place, a task already handed out keeps its parts.
+	// Whether the last chunk was applied is kept in the metadata of the stored artifact.
+	var artifact *model.Artifact
+	switch {
+	case event.Append && index < 0:
+		return nil, exception.NewInvalidParamsError(fmt.Errorf("artifact %s of task %s not found, there is nothing to append to", artifactID, task.ID))
+	case event.Append && task.Artifacts[index].IsComplete():
+		return nil, exception.NewInvalidParamsError(fmt.Errorf("artifact %s of task %s is complete, its last chunk was already applied", artifactID, task.ID))
+	case event.Append:
+		artifact = task.Artifacts[index].AppendChunk(event.Artifact)
+	default:
+		artifact = event.Artifact.Clone()
+	}
+	artifact.SetComplete(event.LastChunk)
+
+	if index >= 0 {
+		task.Artifacts[index] = artifact
+	} else {
+		task.Artifacts = append(task.Artifacts, artifact)
+	}
+
+	return task, nil
+}
+
+// ListTasks 返回所有任务的副本
+func (m *InMemoryTaskManager) ListTasks(ctx context.Context) ([]*model.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tasks, err := m.taskStore.ListTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, task := range tasks {
+		tasks[i] = task.Clone()
+	}
+	return tasks, nil
+}
+
+// DeleteTask removes a task and forgets it in its context
+func (m *InMemoryTaskManager) DeleteTask(ctx context.Context, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, err := m.taskStore.Load(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to load task: %w", err)
+	}
+	if task == nil {
+		return nil
+	}
+	if taskIDs, ok := m.contextTaskIDs[task.ContextID]; ok {
+		delete(taskIDs, taskID)
+		if len(taskIDs) == 0 {
+			delete(m.contextTaskIDs, task.ContextID)
+		}
+	}
+	return m.taskStore.Delete(ctx, taskID)
+}
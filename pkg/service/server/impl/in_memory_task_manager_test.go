@@ -0,0 +1,308 @@
+package impl_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+	"github.com/a2ap/a2ago/pkg/service/server/impl"
+)
+
+// createTask sends a first message for taskID and returns the submitted task
+func createTask(t *testing.T, manager server.TaskManager, taskID string) *model.Task {
+	t.Helper()
+	params := model.NewMessageSendParams(model.NewUserMessage(taskID, "context-1", []model.Part{model.NewTextPart("hello")}), nil)
+	requestCtx, err := manager.LoadOrCreateContext(context.Background(), params)
+	if err != nil {
+		t.Fatalf("LoadOrCreateContext() error = %v", err)
+	}
+	return requestCtx.Task
+}
+
+// updateStatus applies a status update with the given state and reason to a task
+func updateStatus(manager server.TaskManager, task *model.Task, state model.TaskState, reason string) (*model.Task, error) {
+	event := &model.TaskStatusUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Kind:      model.KindStatusUpdate,
+		Status:    model.NewTaskStatus(state),
+		Final:     state.IsFinal(),
+	}
+	if reason != "" {
+		event.Metadata = map[string]interface{}{model.StatusReasonKey: reason}
+	}
+	return manager.ApplyStatusUpdate(context.Background(), task, event)
+}
+
+// addChunk applies an artifact update to a task
+func addChunk(manager server.TaskManager, task *model.Task, artifact *model.Artifact, append, lastChunk bool) (*model.Task, error) {
+	return manager.ApplyArtifactUpdate(context.Background(), task, &model.TaskArtifactUpdateEvent{
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Kind:      model.KindArtifactUpdate,
+		Artifact:  artifact,
+		Append:    append,
+		LastChunk: lastChunk,
+	})
+}
+
+// textArtifact creates an artifact with one text part per text
+func textArtifact(id, name string, texts ...string) *model.Artifact {
+	parts := make([]model.Part, 0, len(texts))
+	for _, text := range texts {
+		parts = append(parts, model.NewTextPart(text))
+	}
+	return model.NewArtifact(id, name).WithParts(parts)
+}
+
+func TestReturnedTasksAreCopies(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	task := createTask(t, manager, "task-copy")
+	task, err := addChunk(manager, task, textArtifact("report", "Report", "one"), false, false)
+	if err != nil {
+		t.Fatalf("ApplyArtifactUpdate() error = %v", err)
+	}
+
+	handedOut, err := manager.GetTask(context.Background(), "task-copy")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if _, err := addChunk(manager, task, textArtifact("report", "", "two"), true, false); err != nil {
+		t.Fatalf("ApplyArtifactUpdate() error = %v", err)
+	}
+	if _, err := updateStatus(manager, task, model.TaskStateWorking, ""); err != nil {
+		t.Fatalf("ApplyStatusUpdate() error = %v", err)
+	}
+
+	if parts := handedOut.GetArtifact("report").Parts; len(parts) != 1 {
+		t.Errorf("handed out report = %d parts, want the later chunk not to change it", len(parts))
+	}
+	if handedOut.Status.State != model.TaskStateSubmitted || len(handedOut.StatusHistory) != 1 {
+		t.Errorf("handed out task = %s, want the later status update not to change it", handedOut.Status.State)
+	}
+
+	handedOut.History = nil
+	stored, _ := manager.GetTask(context.Background(), "task-copy")
+	if len(stored.History) != 1 || len(stored.GetArtifact("report").Parts) != 2 {
+		t.Errorf("stored task = %+v, want it unaffected by changes to a copy", stored)
+	}
+}
+
+func TestStatusUpdatesFollowTheLifecycle(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	task := createTask(t, manager, "task-lifecycle")
+
+	var err error
+	if task, err = updateStatus(manager, task, model.TaskStateWorking, ""); err != nil {
+		t.Fatalf("ApplyStatusUpdate(working) error = %v", err)
+	}
+	if task, err = updateStatus(manager, task, model.TaskStateCompleted, ""); err != nil {
+		t.Fatalf("ApplyStatusUpdate(completed) error = %v", err)
+	}
+	if _, err := updateStatus(manager, task, model.TaskStateCompleted, ""); err != nil {
+		t.Errorf("ApplyStatusUpdate() repeating the final state error = %v, want a no-op", err)
+	}
+
+	var transitionErr *exception.InvalidTransitionError
+	if _, err := updateStatus(manager, task, model.TaskStateWorking, ""); !errors.As(err, &transitionErr) {
+		t.Errorf("ApplyStatusUpdate() leaving a final state error = %v, want InvalidTransitionError", err)
+	}
+	var terminalErr *exception.TaskTerminalError
+	if _, err := addChunk(manager, task, textArtifact("late", "Late", "x"), false, true); !errors.As(err, &terminalErr) {
+		t.Errorf("ApplyArtifactUpdate() on a completed task error = %v, want TaskTerminalError", err)
+	}
+	params := model.NewMessageSendParams(model.NewUserMessage("task-lifecycle", "context-1", []model.Part{model.NewTextPart("again")}), nil)
+	if _, err := manager.LoadOrCreateContext(context.Background(), params); !errors.As(err, &terminalErr) {
+		t.Errorf("LoadOrCreateContext() for a completed task error = %v, want TaskTerminalError", err)
+	}
+
+	stored, err := manager.GetTask(context.Background(), "task-lifecycle")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if stored.Status.State != model.TaskStateCompleted {
+		t.Errorf("stored state = %s, want completed", stored.Status.State)
+	}
+}
+
+func TestStatusHistoryRecordsTransitions(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	task := createTask(t, manager, "task-history")
+	if _, err := updateStatus(manager, task, model.TaskStateWorking, "started"); err != nil {
+		t.Fatalf("ApplyStatusUpdate(working) error = %v", err)
+	}
+	if _, err := manager.CancelTask(context.Background(), "task-history"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+
+	stored, err := manager.GetTask(context.Background(), "task-history")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	want := []model.TaskStatusTransition{
+		{State: model.TaskStateSubmitted},
+		{From: model.TaskStateSubmitted, State: model.TaskStateWorking, Reason: "started"},
+		{From: model.TaskStateWorking, State: model.TaskStateCanceled},
+	}
+	if len(stored.StatusHistory) != len(want) {
+		t.Fatalf("status history = %+v, want %+v", stored.StatusHistory, want)
+	}
+	for i, transition := range stored.StatusHistory {
+		if transition.From != want[i].From || transition.State != want[i].State || transition.Timestamp == "" {
+			t.Errorf("status history[%d] = %+v, want %s -> %s with a timestamp", i, transition, want[i].From, want[i].State)
+		}
+		if want[i].Reason != "" && transition.Reason != want[i].Reason {
+			t.Errorf("status history[%d] reason = %q, want %q", i, transition.Reason, want[i].Reason)
+		}
+	}
+	if last := stored.StatusHistory[2]; last.Reason == "" {
+		t.Errorf("canceled transition = %+v, want a reason", last)
+	}
+}
+
+func TestFollowUpMessageResumesTheTask(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	task := createTask(t, manager, "task-follow-up")
+	task, _ = updateStatus(manager, task, model.TaskStateWorking, "")
+	if _, err := updateStatus(manager, task, model.TaskStateInputRequired, ""); err != nil {
+		t.Fatalf("ApplyStatusUpdate(input-required) error = %v", err)
+	}
+
+	resumed := createTask(t, manager, "task-follow-up")
+	if resumed.Status.State != model.TaskStateWorking || len(resumed.History) != 2 {
+		t.Errorf("follow-up task = %s with %d messages, want working with both messages", resumed.Status.State, len(resumed.History))
+	}
+	last := resumed.StatusHistory[len(resumed.StatusHistory)-1]
+	if last.From != model.TaskStateInputRequired || last.Reason != "input received" {
+		t.Errorf("last transition = %+v, want input-required -> working for the input received", last)
+	}
+}
+
+func TestArtifactChunksAreAssembled(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	task := createTask(t, manager, "task-chunks")
+
+	chunks := []struct {
+		artifact          *model.Artifact
+		append, lastChunk bool
+	}{
+		{textArtifact("report", "Draft", "one"), false, false},
+		{textArtifact("report", "", "two"), true, false},
+		{textArtifact("report", "Report", "three").WithDescription("final"), true, true},
+		{model.NewArtifact("empty", "Empty"), false, true},
+	}
+	var err error
+	for _, chunk := range chunks {
+		if task, err = addChunk(manager, task, chunk.artifact, chunk.append, chunk.lastChunk); err != nil {
+			t.Fatalf("ApplyArtifactUpdate(%s) error = %v", chunk.artifact.ArtifactID, err)
+		}
+	}
+	var a2aErr *exception.A2AError
+	if _, err := addChunk(manager, task, textArtifact("report", "", "late"), true, false); !errors.As(err, &a2aErr) || a2aErr.Code != exception.InvalidParams {
+		t.Errorf("ApplyArtifactUpdate() after the last chunk error = %v, want InvalidParams", err)
+	}
+	if _, err := addChunk(manager, task, textArtifact("unknown", "", "orphan"), true, false); !errors.As(err, &a2aErr) || a2aErr.Code != exception.InvalidParams {
+		t.Errorf("ApplyArtifactUpdate() appending to an unknown artifact error = %v, want InvalidParams", err)
+	}
+
+	stored, err := manager.GetTask(context.Background(), "task-chunks")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if len(stored.Artifacts) != 2 {
+		t.Fatalf("artifacts = %+v, want the report and the empty artifact", stored.Artifacts)
+	}
+	report := stored.GetArtifact("report")
+	if report == nil || report.Name != "Report" || report.Description != "final" {
+		t.Fatalf("report = %+v, want the name and description of the last chunk", report)
+	}
+	if !report.IsComplete() {
+		t.Errorf("report metadata = %v, want it marked complete", report.Metadata)
+	}
+	if want := textArtifact("report", "", "one", "two", "three").Parts; !reflect.DeepEqual(report.Parts, want) {
+		t.Errorf("report parts = %+v, want one, two, three without the chunk after the last one", report.Parts)
+	}
+	if empty := stored.GetArtifact("empty"); empty == nil || len(empty.Parts) != 0 {
+		t.Errorf("empty artifact = %+v, want it stored without parts", empty)
+	}
+
+	// Sending the artifact again without append replaces it and accepts new chunks
+	if task, err = addChunk(manager, stored, textArtifact("report", "Report", "new"), false, false); err != nil {
+		t.Fatalf("ApplyArtifactUpdate() replacing the report error = %v", err)
+	}
+	if task.GetArtifact("report").IsComplete() {
+		t.Error("replaced report is marked complete, want it open for new chunks")
+	}
+	if task, err = addChunk(manager, task, textArtifact("report", "", "more"), true, true); err != nil {
+		t.Fatalf("ApplyArtifactUpdate() appending to the replaced report error = %v", err)
+	}
+	if parts := task.GetArtifact("report").Parts; len(parts) != 2 {
+		t.Errorf("replaced report parts = %+v, want new and more", parts)
+	}
+}
+
+func TestQueryTaskLimitsHistory(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	task := createTask(t, manager, "task-history")
+	status := model.NewTaskStatus(model.TaskStateCompleted)
+	status.Message = model.NewAgentMessage(task.ID, task.ContextID, []model.Part{model.NewTextPart("done")})
+	if _, err := manager.ApplyStatusUpdate(context.Background(), task, &model.TaskStatusUpdateEvent{TaskID: task.ID, Status: status, Final: true}); err != nil {
+		t.Fatalf("ApplyStatusUpdate() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		params *model.TaskQueryParams
+		want   int
+	}{
+		{model.NewTaskQueryParamsWithHistory("task-history", 0), 0},
+		{model.NewTaskQueryParamsWithHistory("task-history", 1), 1},
+		{model.NewTaskQueryParamsWithHistory("task-history", 5), 2},
+		{model.NewTaskQueryParams("task-history"), 2},
+	} {
+		queried, err := manager.QueryTask(context.Background(), tt.params)
+		if err != nil {
+			t.Fatalf("QueryTask(%v) error = %v", tt.params.HistoryLength, err)
+		}
+		if len(queried.History) != tt.want {
+			t.Errorf("QueryTask(%v) history = %d messages, want %d", tt.params.HistoryLength, len(queried.History), tt.want)
+		}
+	}
+
+	stored, _ := manager.GetTask(context.Background(), "task-history")
+	if len(stored.History) != 2 {
+		t.Errorf("stored history = %d messages, want QueryTask to leave it whole", len(stored.History))
+	}
+}
+
+func TestStoredMessagesCarryTheAssignedIDs(t *testing.T) {
+	manager := impl.NewInMemoryTaskManager(impl.NewInMemoryTaskStore())
+	first := model.NewUserMessage("", "", []model.Part{model.NewTextPart("hello")})
+	requestCtx, err := manager.LoadOrCreateContext(context.Background(), model.NewMessageSendParams(first, nil))
+	if err != nil {
+		t.Fatalf("LoadOrCreateContext() error = %v", err)
+	}
+	task := requestCtx.Task
+	if first.TaskID != "" || first.ContextID != "" {
+		t.Errorf("sent message = %+v, want it left unchanged", first)
+	}
+
+	followUp := model.NewUserMessage(task.ID, "", []model.Part{model.NewTextPart("more")})
+	if _, err := manager.LoadOrCreateContext(context.Background(), model.NewMessageSendParams(followUp, nil)); err != nil {
+		t.Fatalf("LoadOrCreateContext() follow-up error = %v", err)
+	}
+
+	stored, err := manager.GetTask(context.Background(), task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	messages := append([]*model.Message{requestCtx.Message}, stored.History...)
+	for i, message := range messages {
+		if message.TaskID != task.ID || message.ContextID != task.ContextID {
+			t.Errorf("message %d = %s/%s, want the task %s/%s", i, message.TaskID, message.ContextID, task.ID, task.ContextID)
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/a2ap/a2ago/pkg/service/server"
 
-	"github.com/a2ap/a2ago/internal/model"
+	"github.com/a2ap/a2ago/pkg/model"
 )
 
 // InMemoryTaskStore 是 TaskStore 接口的内存实现
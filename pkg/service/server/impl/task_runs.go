@@ -0,0 +1,547 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+// DefaultCancelGracePeriod is how long CancelTask waits for a canceled executor to return
+const DefaultCancelGracePeriod = 5 * time.Second
+
+// runInputBuffer is how many follow-up messages a running executor may have pending
+const runInputBuffer = 8
+
+// ServerOption configures a DefaultA2AServer
+type ServerOption func(*DefaultA2AServer)
+
+// WithCancelGracePeriod sets how long CancelTask waits for a canceled executor to return
+func WithCancelGracePeriod(gracePeriod time.Duration) ServerOption {
+	return func(s *DefaultA2AServer) {
+		s.cancelGracePeriod = gracePeriod
+	}
+}
+
+// WithExecutionTimeout bounds how long an executor may run a task, zero means no bound.
+// A task whose executor runs out of time is failed with the timeout reason.
+func WithExecutionTimeout(timeout time.Duration) ServerOption {
+	return func(s *DefaultA2AServer) {
+		s.executionTimeout = timeout
+	}
+}
+
+// WithSkillTimeout overrides the execution timeout for messages selecting the given agent skill
+func WithSkillTimeout(skillID string, timeout time.Duration) ServerOption {
+	return func(s *DefaultA2AServer) {
+		s.skillTimeouts[skillID] = timeout
+	}
+}
+
+// errExecutionTimeout is the cancellation cause of a run whose deadline passed
+var errExecutionTimeout = errors.New("task execution timed out")
+
+// timedOut reports whether a run context was canceled because the run deadline passed
+func timedOut(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errExecutionTimeout)
+}
+
+// taskRun tracks an executor running a task on behalf of the server
+type taskRun struct {
+	taskID string
+	cancel context.CancelFunc
+	done   chan struct{}       // closed once the executor has returned
+	input  chan *model.Message // follow-up messages read by the executor through server.AwaitInput
+
+	progressMu sync.Mutex
+	task       *model.Task    // the task as updated by the event loop of the run
+	applied    uint64         // sequence id of the last event applied to the task
+	progress   chan struct{}  // closed and replaced whenever an event is applied
+	reply      *model.Message // direct message the agent answered the request with, instead of a task
+}
+
+// currentTask returns the task as last updated by the event loop of the run
+func (r *taskRun) currentTask() *model.Task {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+	return r.task
+}
+
+// setTask records the task as updated by the event loop of the run
+func (r *taskRun) setTask(task *model.Task) {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+	r.task = task
+}
+
+// setReply records the direct message the agent answered the request with
+func (r *taskRun) setReply(message *model.Message) {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+	r.reply = message
+}
+
+// directReply returns the direct message the agent answered the request with, nil when it works on a task
+func (r *taskRun) directReply() *model.Message {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+	return r.reply
+}
+
+// markApplied records that the event loop of the run applied the event with the given sequence id
+func (r *taskRun) markApplied(seq uint64) {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+	if seq > r.applied {
+		r.applied = seq
+	}
+	close(r.progress)
+	r.progress = make(chan struct{})
+}
+
+// waitApplied waits until the event loop of the run applied the event with the given sequence id,
+// the executor returned or ctx is done
+func (r *taskRun) waitApplied(ctx context.Context, seq uint64) error {
+	for {
+		r.progressMu.Lock()
+		applied, progress := r.applied, r.progress
+		r.progressMu.Unlock()
+		if applied >= seq {
+			return nil
+		}
+		select {
+		case <-progress:
+		case <-r.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// runStart is a message request admitted to run its task: either a new run of the executor,
+// or a follow-up message handed to the executor still running the task
+type runStart struct {
+	taskCtx    *model.RequestContext // the request context read by the server
+	requestCtx *model.RequestContext // the copy of taskCtx handed to the executor
+	queue      *server.EventQueue
+	run        *taskRun
+	runCtx     context.Context
+	slot       *poolSlot
+	tap        *server.EventQueue // observes the continued run when the message went to a running executor
+}
+
+// beginRun validates a message request, reserves its execution slot and loads or creates its task.
+// A follow-up message for a task whose executor still runs is handed to that executor and the returned
+// start only carries the run and a tap to observe it. Otherwise the queue of the task is created and a
+// new run is registered with an execution context derived from parent; the caller executes it.
+func (s *DefaultA2AServer) beginRun(ctx context.Context, params *model.MessageSendParams, parent context.Context) (*runStart, error) {
+	if params == nil {
+		return nil, fmt.Errorf("params cannot be nil")
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, exception.NewInvalidParamsError(err)
+	}
+
+	// Reserve an execution slot before the task is created, so a busy server leaves no state behind.
+	// The execution owns the slot once it starts, every other path gives it back.
+	slot, err := s.admit(params)
+	if err != nil {
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			slot.release()
+		}
+	}()
+
+	// Requests for the same task decide one at a time between continuing its run and starting a new one
+	unlock := s.lockTask(params.Message.TaskID)
+	defer unlock()
+
+	// Load or create task context
+	taskCtx, err := s.taskManager.LoadOrCreateContext(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load or create task context: %w", err)
+	}
+
+	// An executor still running the task receives the follow-up message instead of a new execution
+	tap, run, err := s.continueRun(ctx, taskCtx.TaskID, params.Message)
+	if err != nil {
+		return nil, err
+	}
+	if tap != nil {
+		return &runStart{taskCtx: taskCtx, run: run, tap: tap}, nil
+	}
+
+	// Create queue
+	queue, err := s.queueManager.Create(ctx, taskCtx.TaskID, lifecycleValidator(taskCtx.Task))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue: %w", err)
+	}
+
+	// The executor works on its own copy of the request context, taskCtx is only read from here on
+	requestCtx := executorContext(taskCtx)
+	runCtx, run := s.startRun(parent, taskCtx)
+	started = true
+	return &runStart{
+		taskCtx:    taskCtx,
+		requestCtx: requestCtx,
+		queue:      queue,
+		run:        run,
+		runCtx:     runCtx,
+		slot:       slot,
+	}, nil
+}
+
+// applyEvents applies every event of the queue of a run to its task until the queue is closed and drained.
+// Each applied event is handed to observe, if set, until observe returns false.
+// The returned channel is closed once the last event is applied.
+func (s *DefaultA2AServer) applyEvents(ctx context.Context, run *taskRun, queue *server.EventQueue, observe func(server.QueueEvent) bool) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range queue.Sequenced() {
+			if err := s.applyEvent(ctx, run, event); err != nil {
+				continue
+			}
+			if observe != nil && !observe(event) {
+				observe = nil
+			}
+		}
+	}()
+	return done
+}
+
+// runToEnd executes a new run and applies its events until the executor returned and every event is
+// applied, then unregisters the run. A run that timed out leaves its task failed, a canceled run canceled.
+// It returns the failure of the executor reported to a blocking caller.
+func (s *DefaultA2AServer) runToEnd(ctx context.Context, start *runStart) error {
+	taskCtx, run, queue := start.taskCtx, start.run, start.queue
+	// The events are persisted even after the run or its request is canceled
+	ctx = context.WithoutCancel(ctx)
+	applied := s.applyEvents(ctx, run, queue, nil)
+
+	err := s.execute(start.runCtx, start.slot, start.requestCtx, queue)
+	canceled := false
+	switch {
+	case timedOut(start.runCtx):
+		// The deadline already failed the task, the caller gets the failed task
+		err = nil
+	case start.runCtx.Err() != nil:
+		canceled = true
+		err = nil
+	case err != nil:
+		err = s.reportFailure(taskCtx, queue, err)
+	}
+	queue.Close()
+	<-applied
+	if err := s.queueManager.Remove(ctx, taskCtx.TaskID, queue); err != nil {
+		log.Printf("Error removing queue for task %s: %v", taskCtx.TaskID, err)
+	}
+	s.finishRun(taskCtx.TaskID, run)
+
+	// A direct reply leaves the task to the caller
+	if canceled && run.directReply() == nil {
+		if canceledTask, cancelErr := s.persistCanceled(ctx, taskCtx.TaskID); cancelErr != nil {
+			log.Printf("Error marking task %s as canceled: %v", taskCtx.TaskID, cancelErr)
+		} else {
+			run.setTask(canceledTask)
+		}
+	}
+	return err
+}
+
+// startRun derives the cancelable execution context of a task from parent and registers the run.
+// The context is canceled with errExecutionTimeout once the run deadline passes.
+func (s *DefaultA2AServer) startRun(parent context.Context, taskCtx *model.RequestContext) (context.Context, *taskRun) {
+	ctx, cancel := context.WithCancel(parent)
+	if deadline, ok := s.runDeadline(parent, taskCtx); ok {
+		deadlineCtx, cancelDeadline := context.WithDeadlineCause(ctx, deadline, errExecutionTimeout)
+		cancelRun := cancel
+		ctx, cancel = deadlineCtx, func() {
+			cancelDeadline()
+			cancelRun()
+		}
+	}
+
+	run := &taskRun{
+		taskID:   taskCtx.TaskID,
+		task:     taskCtx.Task,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		input:    make(chan *model.Message, runInputBuffer),
+		progress: make(chan struct{}),
+	}
+	ctx = server.WithInput(ctx, run.input)
+
+	s.runsMu.Lock()
+	s.runs[taskCtx.TaskID] = run
+	s.runsMu.Unlock()
+	return ctx, run
+}
+
+// runDeadline returns the deadline of a run: the execution timeout of the selected skill or the server,
+// moved earlier by a deadline the client supplied in the request metadata or the transport
+func (s *DefaultA2AServer) runDeadline(ctx context.Context, taskCtx *model.RequestContext) (time.Time, bool) {
+	var deadline time.Time
+	timeout, ok := s.skillTimeouts[taskCtx.GetSkillID()]
+	if !ok {
+		timeout = s.executionTimeout
+	}
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for _, clientDeadline := range []func() (time.Time, bool){
+		taskCtx.GetDeadline,
+		func() (time.Time, bool) { return server.ClientDeadlineFromContext(ctx) },
+	} {
+		if d, ok := clientDeadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
+
+// reportTimeout enqueues the final failed status of a task whose run deadline passed
+func (s *DefaultA2AServer) reportTimeout(taskID, contextID string, queue *server.EventQueue) {
+	log.Printf("Execution of task %s timed out", taskID)
+	timeout := exception.NewExecutionTimeoutError(taskID)
+
+	status := model.NewTaskStatus(model.TaskStateFailed)
+	status.Error = timeout.Message
+	status.Metadata = map[string]interface{}{model.StatusReasonKey: model.StatusReasonTimeout}
+	status.Message = model.NewAgentMessage(taskID, contextID, []model.Part{model.NewTextPart(timeout.Message)})
+	if err := queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+		TaskID:    taskID,
+		ContextID: contextID,
+		Kind:      model.KindStatusUpdate,
+		Status:    status,
+		Final:     true,
+	}); err != nil {
+		// The task finished while its deadline passed
+		log.Printf("Task %s was not marked as timed out: %v", taskID, err)
+	}
+}
+
+// finishRun unregisters a run once its executor has returned
+func (s *DefaultA2AServer) finishRun(taskID string, run *taskRun) {
+	s.runsMu.Lock()
+	if s.runs[taskID] == run {
+		delete(s.runs, taskID)
+	}
+	s.runsMu.Unlock()
+
+	run.cancel()
+	close(run.done)
+}
+
+// executorContext returns the request context handed to the executor: a copy with its own task, taken
+// before the run starts, so nothing the executor does with it is seen by the server or the event loop
+func executorContext(taskCtx *model.RequestContext) *model.RequestContext {
+	requestCtx := *taskCtx
+	requestCtx.Task = taskCtx.Task.Clone()
+	return &requestCtx
+}
+
+// executorPanicError reports a panic recovered from an agent executor
+type executorPanicError struct {
+	value interface{}
+}
+
+// Error returns the error message
+func (e *executorPanicError) Error() string {
+	return fmt.Sprintf("agent executor panicked: %v", e.value)
+}
+
+// execute runs the agent executor with requestCtx, its own copy from executorContext, once slot is granted,
+// recovering a panic into an error. When the run deadline passes while the task waits or runs,
+// the task is failed through queue before execute returns.
+func (s *DefaultA2AServer) execute(ctx context.Context, slot *poolSlot, requestCtx *model.RequestContext, queue *server.EventQueue) (err error) {
+	taskID, contextID := requestCtx.TaskID, requestCtx.ContextID
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Agent executor panicked on task %s: %v\n%s", taskID, r, debug.Stack())
+			err = &executorPanicError{value: r}
+		}
+	}()
+
+	reported := make(chan struct{})
+	stopWatch := context.AfterFunc(ctx, func() {
+		defer close(reported)
+		if timedOut(ctx) {
+			s.reportTimeout(taskID, contextID, queue)
+		}
+	})
+	defer func() {
+		if !stopWatch() {
+			<-reported
+		}
+	}()
+
+	if err := slot.wait(ctx); err != nil {
+		return err
+	}
+	defer slot.release()
+	if slot != nil {
+		// The slot is given back while the executor waits for input
+		ctx = server.WithInputWaiter(ctx, slot)
+	}
+	return s.agentExecutor.Execute(ctx, requestCtx, queue)
+}
+
+// executionFailure converts the error of a failed executor into the error reported to clients.
+// Only the message of an A2A error raised by the agent is kept, anything else may expose internals.
+func executionFailure(taskID string, err error) *exception.A2AError {
+	var failure *exception.A2AError
+	if errors.As(err, &failure) && failure.Code == exception.AgentExecutionError {
+		return failure
+	}
+	message := "Agent execution failed"
+	if errors.As(err, &failure) && failure.Message != "" {
+		message = failure.Message
+	}
+	return exception.NewAgentExecutionError(taskID, message, err)
+}
+
+// reportFailure enqueues the final failed status of a task whose executor failed with err,
+// so the event loop of the run persists it and ends the stream with it.
+// It returns the error reported to the caller.
+func (s *DefaultA2AServer) reportFailure(taskCtx *model.RequestContext, queue *server.EventQueue, err error) error {
+	log.Printf("Error executing task %s: %v", taskCtx.TaskID, err)
+	failure := executionFailure(taskCtx.TaskID, err)
+
+	status := model.NewTaskStatus(model.TaskStateFailed)
+	status.Error = failure.Message
+	status.Message = model.NewAgentMessage(taskCtx.TaskID, taskCtx.ContextID, []model.Part{model.NewTextPart(failure.Message)})
+	if err := queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+		TaskID:    taskCtx.TaskID,
+		ContextID: taskCtx.ContextID,
+		Kind:      model.KindStatusUpdate,
+		Status:    status,
+		Final:     true,
+	}); err != nil {
+		// The executor already finished the task before returning its error
+		log.Printf("Task %s was not marked as failed: %v", taskCtx.TaskID, err)
+	}
+	return failure
+}
+
+// taskLock serializes the requests that start or continue the run of a task
+type taskLock struct {
+	mu   sync.Mutex
+	refs int // requests holding or waiting for the lock
+}
+
+// lockTask locks the task against other requests starting or continuing its run and returns the unlock
+// function, which may be called more than once. A request for a new task, without ID, takes no lock.
+func (s *DefaultA2AServer) lockTask(taskID string) func() {
+	if taskID == "" {
+		return func() {}
+	}
+
+	s.taskLocksMu.Lock()
+	lock, ok := s.taskLocks[taskID]
+	if !ok {
+		lock = &taskLock{}
+		s.taskLocks[taskID] = lock
+	}
+	lock.refs++
+	s.taskLocksMu.Unlock()
+
+	lock.mu.Lock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			lock.mu.Unlock()
+			s.taskLocksMu.Lock()
+			lock.refs--
+			if lock.refs == 0 {
+				delete(s.taskLocks, taskID)
+			}
+			s.taskLocksMu.Unlock()
+		})
+	}
+}
+
+// continueRun hands a follow-up message to the executor still running its task.
+// It returns the run and a tap on the task's queue to observe it, or a nil tap when no executor is running.
+// When the executor is already returning, continueRun waits for the run to finish, so the message
+// re-invokes the executor on a new queue once the previous run removed its own.
+func (s *DefaultA2AServer) continueRun(ctx context.Context, taskID string, message *model.Message) (*server.EventQueue, *taskRun, error) {
+	s.runsMu.Lock()
+	run, ok := s.runs[taskID]
+	if !ok {
+		s.runsMu.Unlock()
+		return nil, nil, nil
+	}
+	// Tap before delivering the message so no event of the continued run is missed
+	tap, err := s.queueManager.Tap(ctx, taskID)
+	if err != nil {
+		s.runsMu.Unlock()
+		select {
+		case <-run.done:
+			return nil, nil, nil
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	defer s.runsMu.Unlock()
+
+	select {
+	case run.input <- message:
+		return tap, run, nil
+	default:
+		discard(tap)
+		return nil, nil, fmt.Errorf("executor of task %s has too many pending messages", taskID)
+	}
+}
+
+// discard detaches a tap from its queue and drops the events it still holds
+func discard(tap *server.EventQueue) {
+	tap.Close()
+	go func() {
+		for range tap.Sequenced() {
+		}
+	}()
+}
+
+// stopRun cancels the running executor of a task and waits up to the grace period for it to return.
+// It reports false when the executor is still running after the grace period.
+func (s *DefaultA2AServer) stopRun(taskID string) bool {
+	s.runsMu.Lock()
+	run, ok := s.runs[taskID]
+	s.runsMu.Unlock()
+	if !ok {
+		return true
+	}
+
+	run.cancel()
+	select {
+	case <-run.done:
+		return true
+	case <-time.After(s.cancelGracePeriod):
+		log.Printf("Executor of task %s did not return within %s after cancellation", taskID, s.cancelGracePeriod)
+		return false
+	}
+}
+
+// persistCanceled sets the persisted task to canceled after its run was canceled,
+// a task that already reached a final state keeps it
+func (s *DefaultA2AServer) persistCanceled(ctx context.Context, taskID string) (*model.Task, error) {
+	task, err := s.taskManager.CancelTask(ctx, taskID)
+	if exception.HasCode(err, exception.TaskNotCancelable) {
+		return s.taskManager.GetTask(ctx, taskID)
+	}
+	return task, err
+}
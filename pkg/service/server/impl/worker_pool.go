@@ -0,0 +1,270 @@
+package impl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// DefaultMaxPriority is the highest admission priority a client can request unless WithMaxPriority changes it
+const DefaultMaxPriority = 10
+
+// PoolStats describes the load of a WorkerPool
+type PoolStats struct {
+	// MaxConcurrent is the number of executions allowed to run at the same time
+	MaxConcurrent int
+	// MaxPending is the number of executions allowed to wait for a free slot
+	MaxPending int
+	// Running is the number of executions holding a slot
+	Running int
+	// Pending is the number of executions waiting for a slot
+	Pending int
+	// AwaitingInput is the number of executions waiting for a follow-up message, they hold no slot meanwhile
+	AwaitingInput int
+	// Admitted is the number of executions that got a slot, an execution resuming after input is not counted again
+	Admitted uint64
+	// Rejected is the number of executions refused because the pending queue was full
+	Rejected uint64
+	// Abandoned is the number of executions that gave up waiting, canceled or out of time
+	Abandoned uint64
+	// TotalWait is the time admitted executions spent waiting for a slot
+	TotalWait time.Duration
+	// MaxWait is the longest time an admitted execution waited for a slot
+	MaxWait time.Duration
+}
+
+// AverageWait returns the mean time admitted executions waited for a slot
+func (s PoolStats) AverageWait() time.Duration {
+	if s.Admitted == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Admitted)
+}
+
+// WorkerPool bounds the agent executions a server runs at the same time.
+// Executions beyond the limit wait in a bounded pending queue, highest priority first and
+// in arrival order within a priority; once the queue is full new executions are rejected.
+// An execution waiting for a follow-up message in server.AwaitInput gives its slot back meanwhile
+// and queues for one again, beyond the pending limit, once the message arrives.
+type WorkerPool struct {
+	maxConcurrent int
+	maxPending    int
+
+	mu       sync.Mutex
+	running  int
+	awaiting int         // suspended slots of executions waiting for input
+	pending  []*poolSlot // waiting slots, ordered by priority then arrival
+	stats    PoolStats
+}
+
+// NewWorkerPool creates a pool running at most maxConcurrent executions with up to maxPending waiting.
+// A maxConcurrent below 1 is treated as 1, a negative maxPending as 0.
+func NewWorkerPool(maxConcurrent, maxPending int) *WorkerPool {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxPending < 0 {
+		maxPending = 0
+	}
+	return &WorkerPool{
+		maxConcurrent: maxConcurrent,
+		maxPending:    maxPending,
+	}
+}
+
+// Stats returns the current load and the admission counters of the pool
+func (p *WorkerPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.MaxConcurrent = p.maxConcurrent
+	stats.MaxPending = p.maxPending
+	stats.Running = p.running
+	stats.Pending = len(p.pending)
+	stats.AwaitingInput = p.awaiting
+	return stats
+}
+
+// poolSlot is the reservation of an execution in a WorkerPool.
+// A nil slot stands for an unbounded server, all its methods return immediately.
+type poolSlot struct {
+	pool      *WorkerPool
+	priority  int
+	queued    time.Time
+	ready     chan struct{} // closed once the slot is granted
+	granted   bool
+	released  bool
+	suspended bool // given back while the execution waits for input
+	resumed   bool // granted before, so not counted as a new admission
+}
+
+// reserve takes a free slot or queues a pending one, returning exception.ServerBusy when the queue is full
+func (p *WorkerPool) reserve(priority int) (*poolSlot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	slot := &poolSlot{pool: p, priority: priority, queued: time.Now(), ready: make(chan struct{})}
+	if p.running < p.maxConcurrent && len(p.pending) == 0 {
+		p.grant(slot)
+		return slot, nil
+	}
+	if len(p.pending) >= p.maxPending {
+		p.stats.Rejected++
+		return nil, exception.NewServerBusyError(p.running, len(p.pending))
+	}
+	p.enqueue(slot)
+	return slot, nil
+}
+
+// enqueue adds a slot to the pending queue by priority then arrival, p.mu must be held
+func (p *WorkerPool) enqueue(slot *poolSlot) {
+	i := len(p.pending)
+	for i > 0 && p.pending[i-1].priority < slot.priority {
+		i--
+	}
+	p.pending = append(p.pending, nil)
+	copy(p.pending[i+1:], p.pending[i:])
+	p.pending[i] = slot
+}
+
+// grant hands a slot to an execution, p.mu must be held
+func (p *WorkerPool) grant(slot *poolSlot) {
+	p.running++
+	if !slot.resumed {
+		wait := time.Since(slot.queued)
+		p.stats.Admitted++
+		p.stats.TotalWait += wait
+		if wait > p.stats.MaxWait {
+			p.stats.MaxWait = wait
+		}
+	}
+	slot.granted = true
+	close(slot.ready)
+}
+
+// grantNext hands the freed slot to the first pending execution, p.mu must be held
+func (p *WorkerPool) grantNext() {
+	if len(p.pending) > 0 && p.running < p.maxConcurrent {
+		next := p.pending[0]
+		p.pending = p.pending[1:]
+		p.grant(next)
+	}
+}
+
+// wait blocks until the slot is granted or ctx is done, in which case the slot is released
+func (s *poolSlot) wait(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-s.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.pool.mu.Lock()
+	if !s.granted && !s.released {
+		s.pool.stats.Abandoned++
+	}
+	s.pool.mu.Unlock()
+	s.release()
+	return ctx.Err()
+}
+
+// release frees a granted slot for the next pending execution or withdraws a pending one.
+// Releasing a slot more than once has no effect.
+func (s *poolSlot) release() {
+	if s == nil {
+		return
+	}
+	p := s.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s.released {
+		return
+	}
+	s.released = true
+
+	if s.suspended {
+		s.suspended = false
+		p.awaiting--
+		return
+	}
+	if !s.granted {
+		for i, pending := range p.pending {
+			if pending == s {
+				p.pending = append(p.pending[:i], p.pending[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+
+	p.running--
+	p.grantNext()
+}
+
+// Waiting gives the slot back while the execution waits for a follow-up message in server.AwaitInput
+func (s *poolSlot) Waiting() {
+	p := s.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !s.granted || s.released {
+		return
+	}
+	s.granted = false
+	s.suspended = true
+	p.awaiting++
+	p.running--
+	p.grantNext()
+}
+
+// Resume takes a slot again once the follow-up message arrived, waiting for it like a new execution of
+// the same priority would. It is queued even when the pending queue is full, the execution was admitted before.
+func (s *poolSlot) Resume(ctx context.Context) error {
+	p := s.pool
+	p.mu.Lock()
+	if !s.suspended {
+		p.mu.Unlock()
+		return nil
+	}
+	s.suspended = false
+	s.resumed = true
+	p.awaiting--
+	s.queued = time.Now()
+	s.ready = make(chan struct{})
+	if p.running < p.maxConcurrent && len(p.pending) == 0 {
+		p.grant(s)
+	} else {
+		p.enqueue(s)
+	}
+	p.mu.Unlock()
+	return s.wait(ctx)
+}
+
+// WithWorkerPool bounds the executions of the server with pool.
+// Without a pool every request starts its execution right away.
+func WithWorkerPool(pool *WorkerPool) ServerOption {
+	return func(s *DefaultA2AServer) {
+		s.pool = pool
+	}
+}
+
+// WithMaxPriority sets the highest admission priority a client can request, higher priorities are lowered to it
+func WithMaxPriority(maxPriority int) ServerOption {
+	return func(s *DefaultA2AServer) {
+		s.maxPriority = maxPriority
+	}
+}
+
+// admit reserves an execution slot for a message/send or message/stream request.
+// It fails with exception.ServerBusy when the pool has no room for the request.
+func (s *DefaultA2AServer) admit(params *model.MessageSendParams) (*poolSlot, error) {
+	if s.pool == nil {
+		return nil, nil
+	}
+	return s.pool.reserve(min(params.GetPriority(), s.maxPriority))
+}
@@ -0,0 +1,187 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+// reserveGranted reserves a slot that must be granted right away
+func reserveGranted(t *testing.T, pool *WorkerPool, priority int) *poolSlot {
+	t.Helper()
+	slot, err := pool.reserve(priority)
+	if err != nil {
+		t.Fatalf("reserve() error = %v", err)
+	}
+	if !isReady(slot) {
+		t.Fatal("reserve() did not grant a free slot")
+	}
+	return slot
+}
+
+// isReady reports whether the slot has been granted
+func isReady(slot *poolSlot) bool {
+	select {
+	case <-slot.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestWorkerPoolGrantsByPriorityThenArrival(t *testing.T) {
+	pool := NewWorkerPool(1, 3)
+	running := reserveGranted(t, pool, 0)
+
+	low, _ := pool.reserve(0)
+	high, _ := pool.reserve(5)
+	lowAgain, _ := pool.reserve(0)
+	if _, err := pool.reserve(9); err == nil {
+		t.Fatal("reserve() on a full pending queue succeeded, want ServerBusy")
+	}
+
+	var order []*poolSlot
+	for _, slot := range []*poolSlot{running, high, low, lowAgain} {
+		slot.release()
+		for _, next := range []*poolSlot{high, low, lowAgain} {
+			if isReady(next) && !contains(order, next) {
+				order = append(order, next)
+			}
+		}
+	}
+	if len(order) != 3 || order[0] != high || order[1] != low || order[2] != lowAgain {
+		t.Errorf("grant order = %v, want high, then both low slots in arrival order", order)
+	}
+	if stats := pool.Stats(); stats.Admitted != 4 || stats.Rejected != 1 || stats.Running != 0 {
+		t.Errorf("Stats() = %+v, want 4 admitted, 1 rejected and none running", stats)
+	}
+}
+
+func contains(slots []*poolSlot, slot *poolSlot) bool {
+	for _, s := range slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWorkerPoolAbandonedSlotLeavesTheQueue(t *testing.T) {
+	pool := NewWorkerPool(1, 1)
+	running := reserveGranted(t, pool, 0)
+	waiting, _ := pool.reserve(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := waiting.wait(ctx); err != context.Canceled {
+		t.Fatalf("wait() error = %v, want context.Canceled", err)
+	}
+	if stats := pool.Stats(); stats.Pending != 0 || stats.Abandoned != 1 {
+		t.Errorf("Stats() = %+v, want the abandoned slot out of the queue", stats)
+	}
+	running.release()
+	running.release()
+	if stats := pool.Stats(); stats.Running != 0 {
+		t.Errorf("Stats() after releasing twice = %+v, want none running", stats)
+	}
+}
+
+func TestWorkerPoolLendsTheSlotWhileAwaitingInput(t *testing.T) {
+	pool := NewWorkerPool(1, 1)
+	awaiting := reserveGranted(t, pool, 0)
+	other, _ := pool.reserve(0)
+
+	input := make(chan *model.Message, 1)
+	ctx := server.WithInputWaiter(server.WithInput(context.Background(), input), awaiting)
+	received := make(chan *model.Message)
+	go func() {
+		message, err := server.AwaitInput(ctx)
+		if err != nil {
+			t.Errorf("AwaitInput() error = %v", err)
+		}
+		received <- message
+	}()
+
+	// The waiting execution gives its slot to the pending one
+	if err := other.wait(context.Background()); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	if stats := pool.Stats(); stats.Running != 1 || stats.AwaitingInput != 1 {
+		t.Errorf("Stats() = %+v, want 1 running and 1 awaiting input", stats)
+	}
+
+	// Once the input arrives the execution queues for a slot again, even with a full pending queue
+	blocker, _ := pool.reserve(0)
+	input <- model.NewUserMessage("task-1", "", []model.Part{model.NewTextPart("more")})
+	for deadline := time.Now().Add(5 * time.Second); pool.Stats().Pending != 2; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Stats() = %+v, want the resumed execution pending beyond the limit", pool.Stats())
+		}
+	}
+	select {
+	case <-received:
+		t.Fatal("AwaitInput() returned before a slot was free")
+	default:
+	}
+	if stats := pool.Stats(); stats.AwaitingInput != 0 {
+		t.Errorf("Stats() = %+v, want no execution awaiting input", stats)
+	}
+
+	other.release()
+	blocker.release()
+	select {
+	case message := <-received:
+		if message == nil {
+			t.Fatal("AwaitInput() returned no message")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AwaitInput() did not return once a slot was free")
+	}
+	awaiting.release()
+	if stats := pool.Stats(); stats.Running != 0 || stats.Pending != 0 || stats.Admitted != 3 {
+		t.Errorf("Stats() = %+v, want an idle pool with 3 admissions", stats)
+	}
+}
+
+func TestWorkerPoolReleasesSlotAwaitingInput(t *testing.T) {
+	pool := NewWorkerPool(1, 0)
+	slot := reserveGranted(t, pool, 0)
+	slot.Waiting()
+	slot.release()
+	if err := slot.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume() of a released slot error = %v", err)
+	}
+
+	if stats := pool.Stats(); stats.Running != 0 || stats.AwaitingInput != 0 {
+		t.Errorf("Stats() = %+v, want an idle pool", stats)
+	}
+	reserveGranted(t, pool, 0)
+}
+
+func TestAdmitClampsPriority(t *testing.T) {
+	s := NewDefaultA2AServer(nil, nil, nil, nil, WithWorkerPool(NewWorkerPool(1, 2)), WithMaxPriority(3)).(*DefaultA2AServer)
+	running, err := s.admit(newPriorityParams(0))
+	if err != nil {
+		t.Fatalf("admit() error = %v", err)
+	}
+	first, _ := s.admit(newPriorityParams(3))
+	greedy, _ := s.admit(newPriorityParams(1 << 30))
+
+	if greedy.priority != 3 {
+		t.Errorf("admitted priority = %d, want the maximum 3", greedy.priority)
+	}
+	// Lowered to the maximum, the greedy request no longer overtakes an earlier one
+	running.release()
+	if !isReady(first) || isReady(greedy) {
+		t.Error("the greedy request overtook an earlier request of the maximum priority")
+	}
+}
+
+func newPriorityParams(priority int) *model.MessageSendParams {
+	params := model.NewMessageSendParams(model.NewUserMessage("", "", []model.Part{model.NewTextPart("hello")}), nil)
+	params.SetPriority(priority)
+	return params
+}
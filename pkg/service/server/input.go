@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// ErrInputUnavailable is returned by AwaitInput when the context does not belong to a running task
+var ErrInputUnavailable = errors.New("follow-up input is not available in this context")
+
+// inputKey is the context key of the follow-up messages of a running task
+type inputKey struct{}
+
+// inputWaiterKey is the context key of the InputWaiter of a running task
+type inputWaiterKey struct{}
+
+// InputWaiter is told by AwaitInput when an executor waits for a follow-up message,
+// so the server can lend the resources of the execution to other tasks meanwhile
+type InputWaiter interface {
+	// Waiting is called before AwaitInput blocks
+	Waiting()
+	// Resume is called once the follow-up message arrived, before AwaitInput returns it.
+	// An error, typically ctx being done, is returned by AwaitInput instead of the message.
+	Resume(ctx context.Context) error
+}
+
+// WithInput returns a context whose executor receives the follow-up messages sent on input through AwaitInput
+func WithInput(ctx context.Context, input <-chan *model.Message) context.Context {
+	return context.WithValue(ctx, inputKey{}, input)
+}
+
+// WithInputWaiter returns a context whose executor notifies waiter while it waits in AwaitInput
+func WithInputWaiter(ctx context.Context, waiter InputWaiter) context.Context {
+	return context.WithValue(ctx, inputWaiterKey{}, waiter)
+}
+
+// AwaitInput blocks until the client sends a follow-up message to the task being executed with ctx.
+// An executor that reported input-required can wait here instead of returning; the follow-up message
+// is also appended to the task history and the task is moved back to working before it is delivered.
+func AwaitInput(ctx context.Context) (*model.Message, error) {
+	input, ok := ctx.Value(inputKey{}).(<-chan *model.Message)
+	if !ok {
+		return nil, ErrInputUnavailable
+	}
+	waiter, _ := ctx.Value(inputWaiterKey{}).(InputWaiter)
+	if waiter != nil {
+		waiter.Waiting()
+	}
+	select {
+	case message := <-input:
+		if waiter != nil {
+			if err := waiter.Resume(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return message, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -6,15 +6,21 @@ import (
 
 // QueueManager defines the interface for managing message queues
 type QueueManager interface {
-	// Create creates a new queue for a task
-	Create(ctx context.Context, taskID string) (*EventQueue, error)
+	// Create creates a new queue for a task, opts override the manager's default queue options
+	Create(ctx context.Context, taskID string, opts ...QueueOption) (*EventQueue, error)
 
 	// Get gets a queue for a task
 	Get(ctx context.Context, taskID string) (*EventQueue, error)
 
-	// Tap taps into an existing task's queue to create a child queue
-	Tap(ctx context.Context, taskID string) (*EventQueue, error)
+	// Tap taps into an existing task's queue to create a child queue receiving every future event,
+	// opts override the options inherited from the task's queue
+	Tap(ctx context.Context, taskID string, opts ...QueueOption) (*EventQueue, error)
 
-	// Remove removes a queue for a task
-	Remove(ctx context.Context, taskID string) error
+	// TapFrom taps into an existing task's queue like Tap, first delivering the retained events
+	// with a sequence id greater than afterSeq
+	TapFrom(ctx context.Context, taskID string, afterSeq uint64, opts ...QueueOption) (*EventQueue, error)
+
+	// Remove closes queue and removes it as the queue of the task if it still is.
+	// A queue created later for the same task, by a new run, is left alone.
+	Remove(ctx context.Context, taskID string, queue *EventQueue) error
 }
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// SequencedResponse is a streaming response produced from the queue event with sequence id Seq.
+// Transports emit Seq as the event id, for example the SSE id field, so subscribers can resume from it.
+type SequencedResponse struct {
+	// Response is the streaming response sent to the client
+	Response model.SendStreamingMessageResponse
+	// Seq is the sequence id of the queue event the response was produced from
+	Seq uint64
+}
+
+// NewSequencedResponse creates a new SequencedResponse
+func NewSequencedResponse(response model.SendStreamingMessageResponse, seq uint64) *SequencedResponse {
+	return &SequencedResponse{
+		Response: response,
+		Seq:      seq,
+	}
+}
+
+// IsSendStreamingMessageResponse implements the SendStreamingMessageResponse interface
+func (r *SequencedResponse) IsSendStreamingMessageResponse() {}
+
+// MarshalJSON implements the json.Marshaler interface, only the response is serialized
+func (r *SequencedResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Response)
+}
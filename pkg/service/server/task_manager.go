@@ -3,30 +3,48 @@ package server
 import (
 	"context"
 
-	"github.com/a2ap/a2ago/internal/model"
+	"github.com/a2ap/a2ago/pkg/model"
 )
 
 // TaskManager defines the interface for managing tasks in the A2A system.
 // The TaskManager is responsible for handling the lifecycle and state of tasks.
+// Tasks it returns are copies the caller owns; updates are applied to the latest stored version of a task,
+// never to the task passed in, so a task handed out is not changed behind the back of its reader.
 type TaskManager interface {
-	// LoadOrCreateContext loads or creates a new task context
+	// LoadOrCreateContext loads or creates a new task context.
+	// A message for a task in a final state is rejected with an *exception.TaskTerminalError.
+	// A follow-up message for an existing task is appended to its history, and a task waiting
+	// for input is moved back to working. The tasks listed in the referenceTaskIds of the message are
+	// loaded into the ReferenceTasks of the context; an unknown one is rejected with exception.InvalidParams.
 	LoadOrCreateContext(ctx context.Context, params *model.MessageSendParams) (*model.RequestContext, error)
 
-	// GetTask gets a task by its ID
+	// GetTask returns a copy of a task by its ID, nil if there is no task
 	GetTask(ctx context.Context, taskID string) (*model.Task, error)
 
-	// ApplyTaskUpdate applies a list of task updates
+	// QueryTask returns a copy of a task whose history holds at most the params.HistoryLength most recent
+	// messages, the whole history when it is unset. The stored task is not modified; nil if there is no task.
+	QueryTask(ctx context.Context, params *model.TaskQueryParams) (*model.Task, error)
+
+	// ApplyTaskUpdate applies a list of task updates, a *model.Message is appended to the task history
 	ApplyTaskUpdate(ctx context.Context, task *model.Task, updates []model.TaskUpdate) (*model.Task, error)
 
 	// ApplyTaskUpdate applies a single task update
 	ApplyTaskUpdateSingle(ctx context.Context, task *model.Task, update model.TaskUpdate) (*model.Task, error)
 
-	// ApplyStatusUpdate applies a status update to a task
+	// ApplyStatusUpdate applies a status update to a task.
+	// Transitions the task lifecycle does not allow are rejected with an *exception.InvalidTransitionError.
 	ApplyStatusUpdate(ctx context.Context, task *model.Task, event *model.TaskStatusUpdateEvent) (*model.Task, error)
 
-	// ApplyArtifactUpdate applies an artifact update to a task
+	// ApplyArtifactUpdate applies an artifact update to a task.
+	// An appended chunk extends the parts of the artifact with the same ID, any other update replaces it;
+	// once a chunk marked lastChunk was applied the artifact is complete and further chunks are rejected.
+	// Tasks in a final state are immutable and reject it with an *exception.TaskTerminalError.
 	ApplyArtifactUpdate(ctx context.Context, task *model.Task, event *model.TaskArtifactUpdateEvent) (*model.Task, error)
 
+	// CancelTask atomically persists the canceled status of a task and returns the updated task.
+	// A task already in a final state is left untouched and a TaskNotCancelable A2AError is returned.
+	CancelTask(ctx context.Context, taskID string) (*model.Task, error)
+
 	// RegisterTaskNotification registers a task notification config
 	RegisterTaskNotification(ctx context.Context, config *model.TaskPushNotificationConfig) error
 
@@ -35,4 +53,7 @@ type TaskManager interface {
 
 	// ListTasks returns all tasks
 	ListTasks(ctx context.Context) ([]*model.Task, error)
+
+	// DeleteTask removes a task, for example one the agent answered with a direct message
+	DeleteTask(ctx context.Context, taskID string) error
 }
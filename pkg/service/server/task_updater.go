@@ -0,0 +1,295 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/a2ap/a2ago/internal/util"
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+)
+
+// ErrReplied is returned for events emitted after the agent answered a request with a direct message
+var ErrReplied = errors.New("the agent already replied with a direct message")
+
+// ErrInvalidArtifact is returned by AddArtifact for an artifact update the task cannot accept
+var ErrInvalidArtifact = errors.New("invalid artifact")
+
+// ArtifactOptions describes the artifact built by TaskUpdater.AddArtifact
+type ArtifactOptions struct {
+	// ArtifactID is the ID of the artifact, generated when empty
+	ArtifactID string
+	// Name is the name of the artifact
+	Name string
+	// Description is the description of the artifact
+	Description string
+	// Metadata is the metadata of the artifact
+	Metadata map[string]interface{}
+	// Append extends the artifact emitted earlier with the same ID instead of replacing it
+	Append bool
+	// LastChunk marks the final part of a streamed artifact
+	LastChunk bool
+}
+
+// ArtifactOption configures ArtifactOptions
+type ArtifactOption func(*ArtifactOptions)
+
+// WithArtifactID sets the ID of the artifact
+func WithArtifactID(artifactID string) ArtifactOption {
+	return func(o *ArtifactOptions) {
+		o.ArtifactID = artifactID
+	}
+}
+
+// WithArtifactName sets the name of the artifact
+func WithArtifactName(name string) ArtifactOption {
+	return func(o *ArtifactOptions) {
+		o.Name = name
+	}
+}
+
+// WithArtifactDescription sets the description of the artifact
+func WithArtifactDescription(description string) ArtifactOption {
+	return func(o *ArtifactOptions) {
+		o.Description = description
+	}
+}
+
+// WithArtifactMetadata sets the metadata of the artifact
+func WithArtifactMetadata(metadata map[string]interface{}) ArtifactOption {
+	return func(o *ArtifactOptions) {
+		o.Metadata = metadata
+	}
+}
+
+// WithAppend appends the parts to the artifact emitted earlier with the same ID
+func WithAppend() ArtifactOption {
+	return func(o *ArtifactOptions) {
+		o.Append = true
+	}
+}
+
+// WithLastChunk marks the parts as the final chunk of the artifact
+func WithLastChunk() ArtifactOption {
+	return func(o *ArtifactOptions) {
+		o.LastChunk = true
+	}
+}
+
+// TaskUpdater emits the status and artifact updates of a task to its EventQueue.
+// Every event carries the kind, task ID, context ID, timestamp and final flag the protocol requires,
+// and once the task reached a final state every further update is refused with a TaskTerminalError.
+type TaskUpdater struct {
+	queue     *EventQueue
+	taskID    string
+	contextID string
+
+	mu        sync.Mutex
+	state     model.TaskState // last state emitted
+	replied   bool            // the agent answered with a direct message
+	artifacts map[string]bool // IDs of the artifacts of the task, true once their last chunk was emitted
+}
+
+// NewTaskUpdater creates a new TaskUpdater for the task with the given IDs.
+// It only knows the artifacts it emits itself, see NewTaskUpdaterForRequest.
+func NewTaskUpdater(queue *EventQueue, taskID, contextID string) *TaskUpdater {
+	return &TaskUpdater{
+		queue:     queue,
+		taskID:    taskID,
+		contextID: contextID,
+		artifacts: make(map[string]bool),
+	}
+}
+
+// NewTaskUpdaterForRequest creates a new TaskUpdater for the task of a request,
+// starting from the state and the artifacts the task already has
+func NewTaskUpdaterForRequest(requestCtx *model.RequestContext, queue *EventQueue) *TaskUpdater {
+	u := NewTaskUpdater(queue, requestCtx.TaskID, requestCtx.ContextID)
+	if task := requestCtx.Task; task != nil {
+		if task.Status != nil {
+			u.state = task.Status.State
+		}
+		for _, artifact := range task.Artifacts {
+			u.artifacts[artifact.ArtifactID] = artifact.IsComplete()
+		}
+	}
+	return u
+}
+
+// NewAgentMessage creates an agent message belonging to the task
+func (u *TaskUpdater) NewAgentMessage(parts []model.Part) *model.Message {
+	return model.NewAgentMessage(u.taskID, u.contextID, parts)
+}
+
+// Reply answers the request with a direct message instead of a task.
+// It has to be the first event of the execution: the server returns the message as the result of the request
+// and drops the task, every later update is refused with ErrReplied.
+func (u *TaskUpdater) Reply(parts []model.Part) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := u.checkOpen(); err != nil {
+		return err
+	}
+
+	message := model.NewAgentMessage("", u.contextID, parts)
+	if err := u.queue.EnqueueEvent(message); err != nil {
+		return err
+	}
+	u.replied = true
+	return nil
+}
+
+// StartWork reports that the agent is working on the task
+func (u *TaskUpdater) StartWork(message *model.Message) error {
+	return u.UpdateStatus(model.TaskStateWorking, message)
+}
+
+// UpdateStatus reports a new status of the task with an optional agent message.
+// Final, input-required and auth-required statuses end the current stream of the task.
+func (u *TaskUpdater) UpdateStatus(state model.TaskState, message *model.Message) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := u.checkOpen(); err != nil {
+		return err
+	}
+
+	status := model.NewTaskStatus(state)
+	status.Message = u.bind(message)
+	final := state.IsFinal() || state == model.TaskStateInputRequired || state == model.TaskStateAuthRequired
+	if err := u.queue.EnqueueEvent(&model.TaskStatusUpdateEvent{
+		TaskID:    u.taskID,
+		ContextID: u.contextID,
+		Kind:      model.KindStatusUpdate,
+		Status:    status,
+		Final:     final,
+	}); err != nil {
+		return err
+	}
+	u.state = state
+	return nil
+}
+
+// AddArtifact emits an artifact of the task built from parts and opts, a missing artifact ID is generated.
+// It returns an error wrapping ErrInvalidArtifact for a nil part, for no parts outside a last chunk,
+// and for an append to an artifact the updater does not know or whose last chunk it emitted.
+func (u *TaskUpdater) AddArtifact(parts []model.Part, opts ...ArtifactOption) error {
+	var options ArtifactOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := u.checkOpen(); err != nil {
+		return err
+	}
+	if err := u.checkArtifact(parts, options); err != nil {
+		return err
+	}
+
+	if options.ArtifactID == "" {
+		options.ArtifactID = util.GenerateUUID()
+	}
+	artifact := model.NewArtifact(options.ArtifactID, options.Name).
+		WithDescription(options.Description).
+		WithParts(append([]model.Part(nil), parts...)).
+		WithMetadata(options.Metadata)
+	if err := u.queue.EnqueueEvent(&model.TaskArtifactUpdateEvent{
+		TaskID:    u.taskID,
+		ContextID: u.contextID,
+		Kind:      model.KindArtifactUpdate,
+		Artifact:  artifact,
+		Append:    options.Append,
+		LastChunk: options.LastChunk,
+	}); err != nil {
+		return err
+	}
+	u.artifacts[artifact.ArtifactID] = options.LastChunk
+	return nil
+}
+
+// checkArtifact refuses artifact updates the task cannot accept
+func (u *TaskUpdater) checkArtifact(parts []model.Part, options ArtifactOptions) error {
+	for i, part := range parts {
+		if part == nil {
+			return fmt.Errorf("%w: part %d is nil", ErrInvalidArtifact, i)
+		}
+	}
+	if len(parts) == 0 && !options.LastChunk {
+		return fmt.Errorf("%w: no parts", ErrInvalidArtifact)
+	}
+	if !options.Append {
+		return nil
+	}
+	closed, known := u.artifacts[options.ArtifactID]
+	if !known {
+		return fmt.Errorf("%w: no artifact %q to append to", ErrInvalidArtifact, options.ArtifactID)
+	}
+	if closed {
+		return fmt.Errorf("%w: artifact %q already received its last chunk", ErrInvalidArtifact, options.ArtifactID)
+	}
+	return nil
+}
+
+// RequireInput reports that the agent needs more input from the client
+func (u *TaskUpdater) RequireInput(message *model.Message) error {
+	return u.UpdateStatus(model.TaskStateInputRequired, message)
+}
+
+// RequireAuth reports that the agent needs the client to authenticate
+func (u *TaskUpdater) RequireAuth(message *model.Message) error {
+	return u.UpdateStatus(model.TaskStateAuthRequired, message)
+}
+
+// Complete reports that the task is completed
+func (u *TaskUpdater) Complete(message *model.Message) error {
+	return u.UpdateStatus(model.TaskStateCompleted, message)
+}
+
+// Fail reports that the task failed
+func (u *TaskUpdater) Fail(message *model.Message) error {
+	return u.UpdateStatus(model.TaskStateFailed, message)
+}
+
+// Reject reports that the agent rejected the task
+func (u *TaskUpdater) Reject(message *model.Message) error {
+	return u.UpdateStatus(model.TaskStateRejected, message)
+}
+
+// checkOpen refuses updates once the agent replied or the task reached a final state
+func (u *TaskUpdater) checkOpen() error {
+	if u.replied {
+		return ErrReplied
+	}
+	if u.state.IsFinal() {
+		return exception.NewTaskTerminalError(u.taskID, u.state)
+	}
+	return nil
+}
+
+// bind fills in the task, context, role and kind of an agent message
+func (u *TaskUpdater) bind(message *model.Message) *model.Message {
+	if message == nil {
+		return nil
+	}
+	if message.TaskID == "" {
+		message.TaskID = u.taskID
+	}
+	if message.ContextID == "" {
+		message.ContextID = u.contextID
+	}
+	if message.Role == "" {
+		message.Role = model.RoleAgent
+	}
+	if message.MessageID == "" {
+		message.MessageID = util.GenerateUUID()
+	}
+	if message.Kind == "" {
+		message.Kind = model.KindMessage
+	}
+	return message
+}
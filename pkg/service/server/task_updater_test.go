@@ -0,0 +1,209 @@
+package server_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a2ap/a2ago/pkg/exception"
+	"github.com/a2ap/a2ago/pkg/model"
+	"github.com/a2ap/a2ago/pkg/service/server"
+)
+
+func TestTaskUpdaterStatusEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		update    func(updater *server.TaskUpdater) error
+		wantState model.TaskState
+		wantFinal bool
+	}{
+		{"start work", func(u *server.TaskUpdater) error { return u.StartWork(nil) }, model.TaskStateWorking, false},
+		{"require input", func(u *server.TaskUpdater) error { return u.RequireInput(nil) }, model.TaskStateInputRequired, true},
+		{"require auth", func(u *server.TaskUpdater) error { return u.RequireAuth(nil) }, model.TaskStateAuthRequired, true},
+		{"complete", func(u *server.TaskUpdater) error { return u.Complete(nil) }, model.TaskStateCompleted, true},
+		{"fail", func(u *server.TaskUpdater) error { return u.Fail(nil) }, model.TaskStateFailed, true},
+		{"reject", func(u *server.TaskUpdater) error { return u.Reject(nil) }, model.TaskStateRejected, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := server.NewEventQueue()
+			if err := tt.update(server.NewTaskUpdater(queue, "task-1", "context-1")); err != nil {
+				t.Fatalf("update error = %v", err)
+			}
+
+			events := drainQueue(queue)
+			if len(events) != 1 {
+				t.Fatalf("delivered %v, want one status update", events)
+			}
+			event, ok := events[0].Event.(*model.TaskStatusUpdateEvent)
+			if !ok {
+				t.Fatalf("delivered %T, want *model.TaskStatusUpdateEvent", events[0].Event)
+			}
+			if event.TaskID != "task-1" || event.ContextID != "context-1" || event.Kind != model.KindStatusUpdate {
+				t.Errorf("event = %+v, want the task and context IDs and the status-update kind", event)
+			}
+			if event.Status == nil || event.Status.State != tt.wantState || event.Status.Timestamp == "" {
+				t.Errorf("status = %+v, want %s with a timestamp", event.Status, tt.wantState)
+			}
+			if event.Final != tt.wantFinal {
+				t.Errorf("final = %v, want %v", event.Final, tt.wantFinal)
+			}
+		})
+	}
+}
+
+func TestTaskUpdaterBindsAgentMessages(t *testing.T) {
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdater(queue, "task-1", "context-1")
+	if err := updater.Complete(&model.Message{Parts: []model.Part{model.NewTextPart("done")}}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	message := drainQueue(queue)[0].Event.(*model.TaskStatusUpdateEvent).Status.Message
+	if message.TaskID != "task-1" || message.ContextID != "context-1" || message.Role != model.RoleAgent {
+		t.Errorf("message = %+v, want an agent message bound to the task", message)
+	}
+	if message.MessageID == "" || message.Kind != model.KindMessage {
+		t.Errorf("message = %+v, want a message ID and the message kind", message)
+	}
+}
+
+func TestTaskUpdaterArtifactEvents(t *testing.T) {
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdater(queue, "task-1", "context-1")
+	if err := updater.AddArtifact([]model.Part{model.NewTextPart("42")}, server.WithArtifactName("answer")); err != nil {
+		t.Fatalf("AddArtifact() error = %v", err)
+	}
+	if err := updater.AddArtifact([]model.Part{model.NewTextPart("draft")}, server.WithArtifactID("report")); err != nil {
+		t.Fatalf("AddArtifact() error = %v", err)
+	}
+	if err := updater.AddArtifact(nil, server.WithArtifactID("report"), server.WithArtifactName("Report"), server.WithAppend(), server.WithLastChunk()); err != nil {
+		t.Fatalf("AddArtifact() error = %v", err)
+	}
+
+	events := drainQueue(queue)
+	first := events[0].Event.(*model.TaskArtifactUpdateEvent)
+	if first.Artifact.ArtifactID == "" || first.Artifact.Name != "answer" || len(first.Artifact.Parts) != 1 || first.Append || first.LastChunk {
+		t.Errorf("first event = %+v, want the answer with a generated artifact ID without append or last chunk", first)
+	}
+	last := events[2].Event.(*model.TaskArtifactUpdateEvent)
+	if last.TaskID != "task-1" || last.ContextID != "context-1" || last.Kind != model.KindArtifactUpdate {
+		t.Errorf("last event = %+v, want the task and context IDs and the artifact-update kind", last)
+	}
+	if last.Artifact.ArtifactID != "report" || last.Artifact.Name != "Report" || !last.Append || !last.LastChunk {
+		t.Errorf("last event = %+v, want the report appended as its last chunk", last)
+	}
+}
+
+func TestTaskUpdaterRejectsInvalidArtifacts(t *testing.T) {
+	task := model.NewTask("task-1")
+	task.AddArtifact(model.NewArtifact("earlier", "Earlier"))
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdaterForRequest(&model.RequestContext{TaskID: "task-1", ContextID: "context-1", Task: task}, queue)
+	if err := updater.AddArtifact([]model.Part{model.NewTextPart("done")}, server.WithArtifactID("closed"), server.WithLastChunk()); err != nil {
+		t.Fatalf("AddArtifact() error = %v", err)
+	}
+
+	text := []model.Part{model.NewTextPart("more")}
+	tests := []struct {
+		name  string
+		parts []model.Part
+		opts  []server.ArtifactOption
+	}{
+		{"nil part", []model.Part{nil}, nil},
+		{"no parts", nil, nil},
+		{"append without id", text, []server.ArtifactOption{server.WithAppend()}},
+		{"append to unknown artifact", text, []server.ArtifactOption{server.WithArtifactID("unknown"), server.WithAppend()}},
+		{"append after last chunk", text, []server.ArtifactOption{server.WithArtifactID("closed"), server.WithAppend()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := updater.AddArtifact(tt.parts, tt.opts...); !errors.Is(err, server.ErrInvalidArtifact) {
+				t.Errorf("AddArtifact() error = %v, want ErrInvalidArtifact", err)
+			}
+		})
+	}
+
+	// The artifacts of the task are known to an updater created for the request
+	if err := updater.AddArtifact(text, server.WithArtifactID("earlier"), server.WithAppend()); err != nil {
+		t.Errorf("AddArtifact() appending to an artifact of the task error = %v", err)
+	}
+	if events := drainQueue(queue); len(events) != 2 {
+		t.Errorf("delivered %d events, want only the valid artifact updates", len(events))
+	}
+}
+
+func TestTaskUpdaterRefusesUpdatesAfterFinalState(t *testing.T) {
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdater(queue, "task-1", "context-1")
+	if err := updater.RequireInput(nil); err != nil {
+		t.Fatalf("RequireInput() error = %v", err)
+	}
+	if err := updater.Complete(nil); err != nil {
+		t.Fatalf("Complete() after RequireInput() error = %v", err)
+	}
+
+	var terminalErr *exception.TaskTerminalError
+	if err := updater.Fail(nil); !errors.As(err, &terminalErr) || terminalErr.State != model.TaskStateCompleted {
+		t.Errorf("Fail() after Complete() error = %v, want TaskTerminalError", err)
+	}
+	if err := updater.AddArtifact([]model.Part{model.NewTextPart("late")}); !errors.As(err, &terminalErr) {
+		t.Errorf("AddArtifact() after Complete() error = %v, want TaskTerminalError", err)
+	}
+	if events := drainQueue(queue); len(events) != 2 {
+		t.Errorf("delivered %d events, want only the input-required and completed statuses", len(events))
+	}
+}
+
+func TestTaskUpdaterReplyEndsTheExecution(t *testing.T) {
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdater(queue, "task-1", "context-1")
+	if err := updater.Reply([]model.Part{model.NewTextPart("hi")}); err != nil {
+		t.Fatalf("Reply() error = %v", err)
+	}
+	if err := updater.Complete(nil); !errors.Is(err, server.ErrReplied) {
+		t.Errorf("Complete() after Reply() error = %v, want ErrReplied", err)
+	}
+
+	events := drainQueue(queue)
+	message, ok := events[0].Event.(*model.Message)
+	if len(events) != 1 || !ok {
+		t.Fatalf("delivered %v, want only the direct message", events)
+	}
+	if message.TaskID != "" || message.ContextID != "context-1" || message.Role != model.RoleAgent {
+		t.Errorf("message = %+v, want an agent message outside of any task", message)
+	}
+}
+
+func TestTaskUpdaterForRequestStartsFromTheTaskState(t *testing.T) {
+	task := model.NewTask("task-1")
+	task.Status = model.NewTaskStatus(model.TaskStateCompleted)
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdaterForRequest(&model.RequestContext{TaskID: "task-1", ContextID: "context-1", Task: task}, queue)
+
+	var terminalErr *exception.TaskTerminalError
+	if err := updater.StartWork(nil); !errors.As(err, &terminalErr) || terminalErr.State != model.TaskStateCompleted {
+		t.Errorf("StartWork() on a completed task error = %v, want TaskTerminalError", err)
+	}
+	if events := drainQueue(queue); len(events) != 0 {
+		t.Errorf("delivered %v, want no event", events)
+	}
+}
+
+func TestTaskUpdaterForRequestKnowsCompleteArtifacts(t *testing.T) {
+	task := model.NewTask("task-1")
+	task.Status = model.NewTaskStatus(model.TaskStateWorking)
+	task.Artifacts = []*model.Artifact{
+		model.NewArtifact("open", "Open"),
+		model.NewArtifact("done", "Done").SetComplete(true),
+	}
+	queue := server.NewEventQueue()
+	updater := server.NewTaskUpdaterForRequest(&model.RequestContext{TaskID: "task-1", ContextID: "context-1", Task: task}, queue)
+
+	parts := []model.Part{model.NewTextPart("more")}
+	if err := updater.AddArtifact(parts, server.WithArtifactID("open"), server.WithAppend()); err != nil {
+		t.Errorf("AddArtifact() appending to an open artifact error = %v", err)
+	}
+	if err := updater.AddArtifact(parts, server.WithArtifactID("done"), server.WithAppend()); !errors.Is(err, server.ErrInvalidArtifact) {
+		t.Errorf("AddArtifact() appending to a complete artifact error = %v, want ErrInvalidArtifact", err)
+	}
+}